@@ -6,54 +6,45 @@
 package main
 
 import (
-	// "encoding/json"
-	// "io/ioutil"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
 	"github.com/galasa-dev/githubapp-copyright/pkg/checks"
 	embedded "github.com/galasa-dev/githubapp-copyright/pkg/embedded"
 )
 
 func main() {
 
-	logBuildInfo()
-
-	log.Println("Starting Galasa copyright checks...")
-
 	var err error = nil
 
 	var console checks.Console
 	console, err = checks.NewConsole()
 	if err == nil {
 
-		var parser checks.CommandLineArgParser
-		parser, err = checks.NewCommandLineArgParserImpl(os.Args, console)
-
+		var env checks.Environment
+		env, err = checks.NewEnvironment()
 		if err == nil {
-			var parsedValues *checks.FieldValuesParsed
-			parsedValues, err = parser.Parse()
-			if err == nil {
 
-				gitHubClient := checks.NewGitHubClient(parsedValues.IsDebugEnabled)
+			var parser checks.CommandLineArgParser
+			parser, err = checks.NewCommandLineArgParserImpl(os.Args, console, env)
 
-				var tokenSupplier checks.TokenSupplier
-				tokenSupplier, err = checks.NewTokenSupplier(gitHubClient, parsedValues.GithubAuthKeyFilePath)
+			if err == nil {
+				var parsedValues *checks.FieldValuesParsed
+				parsedValues, err = parser.Parse()
 				if err == nil {
 
-					var checker checks.Checker
-					checker, err = checks.NewChecker(gitHubClient)
-					if err == nil {
-
-						var eventHandler checks.EventHandler
-						eventHandler, err = checks.NewEventHandlerImpl(gitHubClient, checker, tokenSupplier)
-						if err == nil {
-
-							http.HandleFunc("/githubapp/copyright/event_handler", eventHandler.HandleEvent)
-							log.Printf("Listening for http traffic on port 3000...\n")
-							err = http.ListenAndServe(":3000", nil)
-						}
+					switch parsedValues.Command {
+					case checks.COMMAND_CHECK:
+						err = runLocalCheck(console, parsedValues)
+					case checks.COMMAND_FIX:
+						err = runLocalFix(console, parsedValues)
+					default:
+						err = runServer(parsedValues)
 					}
 				}
 			}
@@ -68,6 +59,102 @@ func main() {
 	os.Exit(0)
 }
 
+// Checks the files in a folder on the local file system, writing any problems found to the console.
+func runLocalCheck(console checks.Console, parsedValues *checks.FieldValuesParsed) error {
+	var err error = nil
+
+	if !parsedValues.IsDebugEnabled {
+		// The console output is what people want to see. Only show the logs if asked for.
+		log.SetOutput(io.Discard)
+	}
+
+	var localChecker checks.LocalChecker
+	localChecker, err = checks.NewLocalChecker(console)
+	if err == nil {
+
+		var checkErrors []checkTypes.CheckError
+		var skippedFiles []checkTypes.SkippedFile
+		checkErrors, skippedFiles, err = localChecker.CheckDirectory(parsedValues.Directory)
+		if err == nil {
+
+			checks.ReportSkippedFiles(console, skippedFiles)
+			checks.ReportCheckErrors(console, checkErrors)
+
+			if len(checkErrors) > 0 {
+				err = errors.New(fmt.Sprintf("Found %d copyright problem(s)", len(checkErrors)))
+			}
+		} else {
+			console.Write(fmt.Sprintf("Error: %s\n", err.Error()))
+		}
+	}
+
+	return err
+}
+
+// Fixes the files in a folder on the local file system, writing any problems which couldn't be fixed to the console.
+func runLocalFix(console checks.Console, parsedValues *checks.FieldValuesParsed) error {
+	var err error = nil
+
+	if !parsedValues.IsDebugEnabled {
+		log.SetOutput(io.Discard)
+	}
+
+	var localChecker checks.LocalChecker
+	localChecker, err = checks.NewLocalChecker(console)
+	if err == nil {
+
+		var fixedPaths []string
+		var checkErrors []checkTypes.CheckError
+		fixedPaths, checkErrors, err = localChecker.FixDirectory(parsedValues.Directory)
+		if err == nil {
+
+			checks.ReportFixedFiles(console, fixedPaths)
+
+			if len(checkErrors) > 0 {
+				checks.ReportCheckErrors(console, checkErrors)
+				err = errors.New(fmt.Sprintf("Could not fix %d copyright problem(s)", len(checkErrors)))
+			}
+		} else {
+			console.Write(fmt.Sprintf("Error: %s\n", err.Error()))
+		}
+	}
+
+	return err
+}
+
+// Runs as a github app, checking pull requests as webhooks arrive from github.
+func runServer(parsedValues *checks.FieldValuesParsed) error {
+	var err error = nil
+
+	logBuildInfo()
+
+	log.Println("Starting Galasa copyright checks...")
+
+	log.Printf("Using github API at %s\n", parsedValues.GithubApiUrl)
+	gitHubClient := checks.NewGitHubClient(parsedValues.GithubApiUrl, parsedValues.GithubUploadUrl, parsedValues.IsDebugEnabled)
+
+	var tokenSupplier checks.TokenSupplier
+	tokenSupplier, err = checks.NewTokenSupplier(gitHubClient, parsedValues.GithubAuthKeyFilePath, parsedValues.GithubAppId)
+	if err == nil {
+
+		var checker checks.Checker
+		checker, err = checks.NewChecker(gitHubClient)
+		if err == nil {
+
+			var eventHandler checks.EventHandler
+			eventHandler, err = checks.NewEventHandlerImpl(gitHubClient, checker, tokenSupplier, parsedValues.WebhookSecretFilePath)
+			if err == nil {
+
+				http.HandleFunc("/githubapp/copyright/event_handler", eventHandler.HandleEvent)
+				log.Printf("Listening for http traffic on port 3000...\n")
+				err = http.ListenAndServe(":3000", nil)
+			}
+		}
+	}
+
+	return err
+}
+
 func logBuildInfo() {
 	log.Printf("Copyright Checker\n")
 	log.Printf("Version %s\n", embedded.GetVersion())
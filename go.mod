@@ -5,4 +5,5 @@ go 1.16
 require (
 	github.com/golang-jwt/jwt v3.2.1+incompatible
 	github.com/stretchr/testify v1.8.4
+	gopkg.in/yaml.v3 v3.0.1
 )
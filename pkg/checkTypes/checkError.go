@@ -5,10 +5,36 @@
  */
 package checkTypes
 
+import (
+	"strings"
+)
+
 type CheckError struct {
 	Path     string
 	Message  string
 	Location int
+
+	// Where the problem is in the file. Lines and columns count from 1.
+	// They are 0 if we don't know where in the file the problem is.
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+
+	// How to correct the file, if we know. nil otherwise.
+	Fix *FileFix
+
+	// The file looks like code from somewhere else, which needs legal review rather than our header.
+	IsThirdParty bool
+
+	// The file has no copyright header, or none with its licence, so REUSE information about the file can make up for it.
+	IsMissingHeader bool
+}
+
+// The corrected content of a file which failed the check.
+type FileFix struct {
+	OriginalContent string
+	FixedContent    string
 }
 
 func NewCheckError(path string, message string, location int) *CheckError {
@@ -19,3 +45,36 @@ func NewCheckError(path string, message string, location int) *CheckError {
 	}
 	return checkError
 }
+
+// Creates a check error which covers part of the file content.
+// startOffset is the byte offset of the first character of the problem,
+// endOffset is the byte offset just beyond the last character of the problem.
+func NewCheckErrorForRange(path string, message string, content string, startOffset int, endOffset int) *CheckError {
+	checkError := NewCheckError(path, message, startOffset)
+
+	if endOffset <= startOffset {
+		// Empty range, so point at the start position.
+		endOffset = startOffset + 1
+	}
+
+	checkError.StartLine, checkError.StartColumn = getLineAndColumn(content, startOffset)
+	checkError.EndLine, checkError.EndColumn = getLineAndColumn(content, endOffset-1)
+
+	return checkError
+}
+
+// Converts a byte offset within the content into line and column numbers, counting from 1.
+func getLineAndColumn(content string, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	textBefore := content[:offset]
+	line := strings.Count(textBefore, "\n") + 1
+	column := offset - strings.LastIndex(textBefore, "\n")
+
+	return line, column
+}
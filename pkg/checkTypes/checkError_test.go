@@ -0,0 +1,55 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checkTypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckErrorWithoutRangeHasNoLineNumbers(t *testing.T) {
+	checkError := NewCheckError("test.java", "message", 0)
+	assert.Equal(t, 0, checkError.StartLine)
+	assert.Equal(t, 0, checkError.EndLine)
+}
+
+func TestCheckErrorAtStartOfContentIsLineOneColumnOne(t *testing.T) {
+	checkError := NewCheckErrorForRange("test.java", "message", "hello\nworld\n", 0, 5)
+	assert.Equal(t, 1, checkError.StartLine)
+	assert.Equal(t, 1, checkError.StartColumn)
+	assert.Equal(t, 1, checkError.EndLine)
+	assert.Equal(t, 5, checkError.EndColumn)
+	assert.Equal(t, 0, checkError.Location)
+}
+
+func TestCheckErrorSpanningLinesGetsStartAndEndLines(t *testing.T) {
+	content := "line one\nline two\nline three\n"
+	start := len("line one\nline ")
+	end := len("line one\nline two\nline three")
+
+	checkError := NewCheckErrorForRange("test.java", "message", content, start, end)
+
+	assert.Equal(t, 2, checkError.StartLine)
+	assert.Equal(t, 6, checkError.StartColumn)
+	assert.Equal(t, 3, checkError.EndLine)
+	assert.Equal(t, 10, checkError.EndColumn)
+	assert.Equal(t, start, checkError.Location)
+}
+
+func TestCheckErrorEndingWithNewLineStaysOnThatLine(t *testing.T) {
+	checkError := NewCheckErrorForRange("test.java", "message", "line one\nline two\n", 0, len("line one\n"))
+	assert.Equal(t, 1, checkError.StartLine)
+	assert.Equal(t, 1, checkError.EndLine)
+}
+
+func TestCheckErrorWithEmptyRangePointsAtStart(t *testing.T) {
+	checkError := NewCheckErrorForRange("test.java", "message", "line one\nline two\n", 9, 9)
+	assert.Equal(t, 2, checkError.StartLine)
+	assert.Equal(t, 1, checkError.StartColumn)
+	assert.Equal(t, 2, checkError.EndLine)
+	assert.Equal(t, 1, checkError.EndColumn)
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checkTypes
+
+// A file which would normally be checked, but was left alone. eg: Because it is generated code
+type SkippedFile struct {
+	Path string
+
+	// Why the file was not checked. eg: "binary content"
+	Reason string
+
+	// The file asked not to be checked, with a copyright-check directive. The reason is the directive.
+	IsSuppressed bool
+}
+
+func NewSkippedFile(path string, reason string) *SkippedFile {
+	return &SkippedFile{
+		Path:   path,
+		Reason: reason,
+	}
+}
+
+// Creates a skipped file for a file which has a copyright-check directive in it.
+func NewSuppressedFile(path string, directive string) *SkippedFile {
+	skippedFile := NewSkippedFile(path, directive)
+	skippedFile.IsSuppressed = true
+	return skippedFile
+}
@@ -9,12 +9,31 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 )
 
 type FieldValuesParsed struct {
+	// What the program has been asked to do. eg: COMMAND_SERVE
+	Command string
+
+	// The folder to check, when checking files on the local file system.
+	Directory string
+
 	GithubAuthKeyFilePath string
+	WebhookSecretFilePath string
 	IsDebugEnabled        bool
+
+	// The id of the github app we authenticate as.
+	GithubAppId int
+
+	// The base URL of the github REST API. eg: https://api.github.com
+	// or https://my-ghes-server/api/v3 for github enterprise server.
+	GithubApiUrl string
+
+	// The base URL which github uses for uploads. eg: https://uploads.github.com
+	// or https://my-ghes-server/api/uploads for github enterprise server.
+	GithubUploadUrl string
 }
 
 type CommandLineArgParser interface {
@@ -24,14 +43,42 @@ type CommandLineArgParser interface {
 type CommandLineArgParserImpl struct {
 	argSequence StringIterator
 	console     Console
+	env         Environment
 }
 
 const (
+	// Run as a github app, listening for webhooks. This is what happens if no command is given.
+	COMMAND_SERVE = "serve"
+
+	// Check the files in a folder on the local file system.
+	COMMAND_CHECK = "check"
+
+	// Correct the headers of the files in a folder on the local file system.
+	COMMAND_FIX = "fix"
+
+	COMMAND_FLAG_DIR                  = "--dir"
 	COMMAND_FLAG_GITHUB_AUTH_KEY_FILE = "--githubAuthKeyFile"
+	COMMAND_FLAG_WEBHOOK_SECRET_FILE  = "--webhookSecretFile"
 	COMMAND_FLAG_DEBUG                = "--debug"
+	COMMAND_FLAG_GITHUB_APP_ID        = "--githubAppId"
+	COMMAND_FLAG_GITHUB_API_URL       = "--githubApiUrl"
+	COMMAND_FLAG_GITHUB_UPLOAD_URL    = "--githubUploadUrl"
+
+	// Environment variables which are used when the equivalent flag is not specified.
+	ENV_VAR_GITHUB_APP_ID     = "GITHUB_APP_ID"
+	ENV_VAR_GITHUB_API_URL    = "GITHUB_API_URL"
+	ENV_VAR_GITHUB_UPLOAD_URL = "GITHUB_UPLOAD_URL"
+
+	DEFAULT_GITHUB_APP_ID     = 125351
+	DEFAULT_GITHUB_API_URL    = "https://api.github.com"
+	DEFAULT_GITHUB_UPLOAD_URL = "https://uploads.github.com"
+
+	// The paths of the REST API and uploads on a github enterprise server.
+	GHES_API_URL_SUFFIX    = "/api/v3"
+	GHES_UPLOAD_URL_SUFFIX = "/api/uploads"
 )
 
-func NewCommandLineArgParserImpl(args []string, console Console) (CommandLineArgParser, error) {
+func NewCommandLineArgParserImpl(args []string, console Console, env Environment) (CommandLineArgParser, error) {
 	var err error = nil
 
 	log.Printf("Command line arguments: %s\n", strings.Join(args, " "))
@@ -40,6 +87,7 @@ func NewCommandLineArgParserImpl(args []string, console Console) (CommandLineArg
 
 	parser.argSequence, err = NewStringIterator(args)
 	parser.console = console
+	parser.env = env
 
 	return parser, err
 }
@@ -48,9 +96,14 @@ func (this *CommandLineArgParserImpl) Parse() (*FieldValuesParsed, error) {
 	var err error = nil
 	results := new(FieldValuesParsed)
 
+	appIdText := ""
+
 	// Skip over the first arg, it's the command which called this program.
 	arg, isDone := this.argSequence.Next()
 
+	results.Command = COMMAND_SERVE
+	isFirstArg := true
+
 	for {
 
 		arg, isDone = this.argSequence.Next()
@@ -59,19 +112,35 @@ func (this *CommandLineArgParserImpl) Parse() (*FieldValuesParsed, error) {
 			break
 		}
 
+		if isFirstArg && !strings.HasPrefix(arg, "-") {
+			// The first arg can be a command. Everything after it is a flag.
+			isFirstArg = false
+			results.Command, err = this.parseCommand(arg)
+			if err != nil {
+				break
+			}
+			continue
+		}
+		isFirstArg = false
+
 		switch arg {
+		case COMMAND_FLAG_DIR:
+			results.Directory, err = this.getFlagValue(COMMAND_FLAG_DIR)
+
 		case COMMAND_FLAG_GITHUB_AUTH_KEY_FILE:
-			{
-				arg, isDone := this.argSequence.Next()
-				if isDone {
-					// Ran out of args, expected a value.
-					msg := fmt.Sprintf("Error: Flag %s requires a value.\n", COMMAND_FLAG_GITHUB_AUTH_KEY_FILE)
-					err = errors.New(msg)
-					this.console.Write(msg)
-				} else {
-					results.GithubAuthKeyFilePath = arg
-				}
-			}
+			results.GithubAuthKeyFilePath, err = this.getFlagValue(COMMAND_FLAG_GITHUB_AUTH_KEY_FILE)
+
+		case COMMAND_FLAG_WEBHOOK_SECRET_FILE:
+			results.WebhookSecretFilePath, err = this.getFlagValue(COMMAND_FLAG_WEBHOOK_SECRET_FILE)
+
+		case COMMAND_FLAG_GITHUB_APP_ID:
+			appIdText, err = this.getFlagValue(COMMAND_FLAG_GITHUB_APP_ID)
+
+		case COMMAND_FLAG_GITHUB_API_URL:
+			results.GithubApiUrl, err = this.getFlagValue(COMMAND_FLAG_GITHUB_API_URL)
+
+		case COMMAND_FLAG_GITHUB_UPLOAD_URL:
+			results.GithubUploadUrl, err = this.getFlagValue(COMMAND_FLAG_GITHUB_UPLOAD_URL)
 
 		case COMMAND_FLAG_DEBUG:
 			{
@@ -89,9 +158,93 @@ func (this *CommandLineArgParserImpl) Parse() (*FieldValuesParsed, error) {
 		}
 	}
 
+	if results.Directory == "" {
+		results.Directory = "."
+	}
+
 	if results.GithubAuthKeyFilePath == "" {
 		results.GithubAuthKeyFilePath = "key.pem"
 	}
 
+	if results.WebhookSecretFilePath == "" {
+		results.WebhookSecretFilePath = "webhook-secret.txt"
+	}
+
+	if err == nil {
+		results.GithubAppId, err = this.parseAppId(appIdText)
+	}
+
+	results.GithubApiUrl = this.defaultUrl(results.GithubApiUrl, ENV_VAR_GITHUB_API_URL, DEFAULT_GITHUB_API_URL)
+	results.GithubUploadUrl = this.defaultUrl(results.GithubUploadUrl, ENV_VAR_GITHUB_UPLOAD_URL, getDefaultUploadUrl(results.GithubApiUrl))
+
 	return results, err
 }
+
+func (this *CommandLineArgParserImpl) parseCommand(arg string) (string, error) {
+	var err error = nil
+	switch arg {
+	case COMMAND_SERVE, COMMAND_CHECK, COMMAND_FIX:
+	default:
+		msg := fmt.Sprintf("Error: Unrecognised command '%s'. Valid commands are: %s, %s, %s\n", arg, COMMAND_SERVE, COMMAND_CHECK, COMMAND_FIX)
+		err = errors.New(msg)
+		this.console.Write(msg)
+	}
+	return arg, err
+}
+
+// Gets the value which follows a flag on the command line.
+func (this *CommandLineArgParserImpl) getFlagValue(flagName string) (string, error) {
+	var err error = nil
+	arg, isDone := this.argSequence.Next()
+	if isDone {
+		// Ran out of args, expected a value.
+		msg := fmt.Sprintf("Error: Flag %s requires a value.\n", flagName)
+		err = errors.New(msg)
+		this.console.Write(msg)
+	}
+	return arg, err
+}
+
+// The app id comes from the command line, then the environment, else we use the default.
+func (this *CommandLineArgParserImpl) parseAppId(appIdText string) (int, error) {
+	var err error = nil
+	appId := DEFAULT_GITHUB_APP_ID
+
+	source := COMMAND_FLAG_GITHUB_APP_ID
+	if appIdText == "" {
+		appIdText = this.env.GetEnv(ENV_VAR_GITHUB_APP_ID)
+		source = ENV_VAR_GITHUB_APP_ID
+	}
+
+	if appIdText != "" {
+		appId, err = strconv.Atoi(appIdText)
+		if err != nil || appId <= 0 {
+			msg := fmt.Sprintf("Error: %s value '%s' is not a valid github app id. It must be a positive whole number.\n", source, appIdText)
+			err = errors.New(msg)
+			this.console.Write(msg)
+		}
+	}
+	return appId, err
+}
+
+// URLs come from the command line, then the environment, else we use the default.
+// Any trailing '/' is removed so URLs can be built by appending paths.
+// Github enterprise server has its uploads alongside its REST API, so the API URL says where they are.
+// eg: https://my-ghes-server/api/v3 has its uploads at https://my-ghes-server/api/uploads
+func getDefaultUploadUrl(apiUrl string) string {
+	uploadUrl := DEFAULT_GITHUB_UPLOAD_URL
+	if apiUrl != DEFAULT_GITHUB_API_URL && strings.HasSuffix(apiUrl, GHES_API_URL_SUFFIX) {
+		uploadUrl = strings.TrimSuffix(apiUrl, GHES_API_URL_SUFFIX) + GHES_UPLOAD_URL_SUFFIX
+	}
+	return uploadUrl
+}
+
+func (this *CommandLineArgParserImpl) defaultUrl(url string, envVarName string, defaultValue string) string {
+	if url == "" {
+		url = this.env.GetEnv(envVarName)
+	}
+	if url == "" {
+		url = defaultValue
+	}
+	return strings.TrimSuffix(url, "/")
+}
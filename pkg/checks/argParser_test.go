@@ -16,7 +16,7 @@ func TestCanCreateAParser(t *testing.T) {
 	args := []string{"copyright"}
 	var console Console
 	console, _ = NewConsole()
-	parser, err := NewCommandLineArgParserImpl(args, console)
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	assert.Nil(t, err)
 	assert.NotNil(t, parser)
 }
@@ -25,7 +25,7 @@ func TestNoArgsReturnsValuesStructure(t *testing.T) {
 	args := []string{"copyright"}
 	var console Console
 	console, _ = NewConsole()
-	parser, err := NewCommandLineArgParserImpl(args, console)
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	values, err := parser.Parse()
 	assert.Nil(t, err)
 	assert.NotNil(t, values)
@@ -35,7 +35,7 @@ func TestNoArgsReturnsValuesDefaultingGithubAuthKeyFilePath(t *testing.T) {
 	args := []string{"copyright"}
 	var console Console
 	console, _ = NewConsole()
-	parser, err := NewCommandLineArgParserImpl(args, console)
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	values, err := parser.Parse()
 	assert.Nil(t, err)
 	assert.NotNil(t, values)
@@ -47,7 +47,7 @@ func TestCanSpecifyGithubAuthKeyFilePath(t *testing.T) {
 	args := []string{"copyright", "--githubAuthKeyFile", "myFilePath"}
 
 	console := NewConsoleMock()
-	parser, err := NewCommandLineArgParserImpl(args, console)
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	values, err := parser.Parse()
 	assert.Nil(t, err)
 	assert.NotNil(t, values)
@@ -58,7 +58,7 @@ func TestGithubAuthKeyFilePathFlagWithNoValueGivesError(t *testing.T) {
 	args := []string{"copyright", "--githubAuthKeyFile"}
 
 	console := NewConsoleMock()
-	parser, _ := NewCommandLineArgParserImpl(args, console)
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	_, err := parser.Parse()
 	assert.NotNil(t, err)
 	assert.Contains(t, console.getAllAsString(), fmt.Sprintf("Error: Flag %s requires a value.\n", COMMAND_FLAG_GITHUB_AUTH_KEY_FILE))
@@ -68,7 +68,7 @@ func TestUnknownParamGivesError(t *testing.T) {
 	args := []string{"copyright", "--githubAuthKeyFile", "myFilePath", "garbage"}
 
 	console := NewConsoleMock()
-	parser, err := NewCommandLineArgParserImpl(args, console)
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	values, err := parser.Parse()
 	assert.NotNil(t, err)
 	assert.True(t, console.contains("Error: Unrecognised parameter 'garbage'"))
@@ -79,7 +79,7 @@ func TestDebugFlagPresentParsesAsDebugEnabled(t *testing.T) {
 	args := []string{"copyright", "--githubAuthKeyFile", "myFilePath", "--debug"}
 
 	console := NewConsoleMock()
-	parser, err := NewCommandLineArgParserImpl(args, console)
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	values, err := parser.Parse()
 	assert.Nil(t, err)
 	assert.NotNil(t, values)
@@ -91,9 +91,195 @@ func TestDebugFlagMissingParsesAsDebugFalse(t *testing.T) {
 	args := []string{"copyright", "--githubAuthKeyFile", "myFilePath"}
 
 	console := NewConsoleMock()
-	parser, err := NewCommandLineArgParserImpl(args, console)
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
 	values, err := parser.Parse()
 	assert.Nil(t, err)
 	assert.NotNil(t, values)
 	assert.Equal(t, false, values.IsDebugEnabled)
 }
+
+func TestNoArgsReturnsValuesDefaultingWebhookSecretFilePath(t *testing.T) {
+	args := []string{"copyright"}
+
+	console := NewConsoleMock()
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.NotNil(t, values)
+	assert.Equal(t, "webhook-secret.txt", values.WebhookSecretFilePath)
+}
+
+func TestCanSpecifyWebhookSecretFilePath(t *testing.T) {
+	args := []string{"copyright", "--webhookSecretFile", "mySecretPath"}
+
+	console := NewConsoleMock()
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.NotNil(t, values)
+	assert.Equal(t, "mySecretPath", values.WebhookSecretFilePath)
+}
+
+func TestWebhookSecretFilePathFlagWithNoValueGivesError(t *testing.T) {
+	args := []string{"copyright", "--webhookSecretFile"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	_, err := parser.Parse()
+	assert.NotNil(t, err)
+	assert.Contains(t, console.getAllAsString(), fmt.Sprintf("Error: Flag %s requires a value.\n", COMMAND_FLAG_WEBHOOK_SECRET_FILE))
+}
+
+func TestNoArgsReturnsValuesDefaultingGithubAppIdAndUrls(t *testing.T) {
+	args := []string{"copyright"}
+
+	console := NewConsoleMock()
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, DEFAULT_GITHUB_APP_ID, values.GithubAppId)
+	assert.Equal(t, "https://api.github.com", values.GithubApiUrl)
+	assert.Equal(t, "https://uploads.github.com", values.GithubUploadUrl)
+}
+
+func TestCanSpecifyGithubAppIdAndUrlsWithFlags(t *testing.T) {
+	args := []string{"copyright",
+		"--githubAppId", "42",
+		"--githubApiUrl", "https://my.ghes/api/v3/",
+		"--githubUploadUrl", "https://my.ghes/api/uploads",
+	}
+
+	console := NewConsoleMock()
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, 42, values.GithubAppId)
+	assert.Equal(t, "https://my.ghes/api/v3", values.GithubApiUrl)
+	assert.Equal(t, "https://my.ghes/api/uploads", values.GithubUploadUrl)
+}
+
+func TestCanSpecifyGithubAppIdAndUrlsWithEnvVars(t *testing.T) {
+	args := []string{"copyright"}
+
+	env := NewEnvironmentMock()
+	env.setEnv(ENV_VAR_GITHUB_APP_ID, "99")
+	env.setEnv(ENV_VAR_GITHUB_API_URL, "https://env.ghes/api/v3")
+	env.setEnv(ENV_VAR_GITHUB_UPLOAD_URL, "https://env.ghes/api/uploads")
+
+	console := NewConsoleMock()
+	parser, err := NewCommandLineArgParserImpl(args, console, env)
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, 99, values.GithubAppId)
+	assert.Equal(t, "https://env.ghes/api/v3", values.GithubApiUrl)
+	assert.Equal(t, "https://env.ghes/api/uploads", values.GithubUploadUrl)
+}
+
+func TestUploadUrlDefaultsToAlongsideGithubEnterpriseServerApiUrl(t *testing.T) {
+	args := []string{"copyright", "--githubApiUrl", "https://my.ghes/api/v3/"}
+
+	console := NewConsoleMock()
+	parser, err := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, "https://my.ghes/api/v3", values.GithubApiUrl)
+	assert.Equal(t, "https://my.ghes/api/uploads", values.GithubUploadUrl)
+}
+
+func TestFlagsOverrideEnvVars(t *testing.T) {
+	args := []string{"copyright", "--githubAppId", "42", "--githubApiUrl", "https://flag.ghes/api/v3"}
+
+	env := NewEnvironmentMock()
+	env.setEnv(ENV_VAR_GITHUB_APP_ID, "99")
+	env.setEnv(ENV_VAR_GITHUB_API_URL, "https://env.ghes/api/v3")
+
+	console := NewConsoleMock()
+	parser, err := NewCommandLineArgParserImpl(args, console, env)
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, 42, values.GithubAppId)
+	assert.Equal(t, "https://flag.ghes/api/v3", values.GithubApiUrl)
+}
+
+func TestNonNumericGithubAppIdGivesError(t *testing.T) {
+	args := []string{"copyright", "--githubAppId", "abc"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	_, err := parser.Parse()
+	assert.NotNil(t, err)
+	assert.True(t, console.contains("Error: --githubAppId value 'abc' is not a valid github app id"))
+}
+
+func TestGithubAppIdFlagWithNoValueGivesError(t *testing.T) {
+	args := []string{"copyright", "--githubAppId"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	_, err := parser.Parse()
+	assert.NotNil(t, err)
+	assert.Contains(t, console.getAllAsString(), fmt.Sprintf("Error: Flag %s requires a value.\n", COMMAND_FLAG_GITHUB_APP_ID))
+}
+
+func TestNoCommandDefaultsToServe(t *testing.T) {
+	args := []string{"copyright", "--githubAuthKeyFile", "myFilePath"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, COMMAND_SERVE, values.Command)
+}
+
+func TestCanSpecifyCheckCommandWithDir(t *testing.T) {
+	args := []string{"copyright", "check", "--dir", "my/folder"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, COMMAND_CHECK, values.Command)
+	assert.Equal(t, "my/folder", values.Directory)
+}
+
+func TestCheckCommandDirDefaultsToCurrentFolder(t *testing.T) {
+	args := []string{"copyright", "check"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, COMMAND_CHECK, values.Command)
+	assert.Equal(t, ".", values.Directory)
+}
+
+func TestUnknownCommandGivesError(t *testing.T) {
+	args := []string{"copyright", "garbage"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	_, err := parser.Parse()
+	assert.NotNil(t, err)
+	assert.True(t, console.contains("Error: Unrecognised command 'garbage'"))
+}
+
+func TestCommandAfterFlagsIsAnUnrecognisedParameter(t *testing.T) {
+	args := []string{"copyright", "--debug", "check"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	_, err := parser.Parse()
+	assert.NotNil(t, err)
+	assert.True(t, console.contains("Error: Unrecognised parameter 'check'"))
+}
+
+func TestCanSpecifyFixCommand(t *testing.T) {
+	args := []string{"copyright", "fix", "--dir", "my/folder"}
+
+	console := NewConsoleMock()
+	parser, _ := NewCommandLineArgParserImpl(args, console, NewEnvironmentMock())
+	values, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, COMMAND_FIX, values.Command)
+	assert.Equal(t, "my/folder", values.Directory)
+}
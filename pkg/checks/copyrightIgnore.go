@@ -0,0 +1,102 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Where a repository can list files which should not be checked at all. eg: vendored code and test fixtures
+// The patterns in it work the same as those in a .gitignore file. See https://git-scm.com/docs/gitignore
+const COPYRIGHT_IGNORE_FILE_PATH = ".copyrightignore"
+
+// The patterns of files which should not be checked.
+type CopyrightIgnore struct {
+	// A pattern starting with '!' brings back files which an earlier pattern ignored,
+	// and a pattern ending with '/' only matches folders, and so everything inside them.
+	rules []*Glob
+}
+
+// Reads the .copyrightignore file of a repository. Repositories without one don't ignore anything.
+func NewCopyrightIgnore(files FileReader) (*CopyrightIgnore, error) {
+	var err error = nil
+	this := new(CopyrightIgnore)
+	this.rules = make([]*Glob, 0)
+
+	var content string
+	var isFound bool
+	content, isFound, err = files.ReadFile(COPYRIGHT_IGNORE_FILE_PATH)
+	if err == nil && isFound {
+		this.rules, err = parseIgnoreRules(content)
+		if err != nil {
+			err = errors.New(fmt.Sprintf("Invalid %s file - %s", COPYRIGHT_IGNORE_FILE_PATH, err.Error()))
+		}
+	}
+
+	return this, err
+}
+
+// Is the file or folder ignored ? The path is relative to the root of the repository, using '/' separators.
+// As in git, a file can't be brought back by a '!' pattern if a folder it is in is ignored.
+func (this *CopyrightIgnore) IsIgnored(path string, isDirectory bool) bool {
+	isIgnored := false
+
+	folders := strings.Split(path, "/")
+	for index := 1; index < len(folders) && !isIgnored; index++ {
+		isIgnored = this.isMatched(strings.Join(folders[:index], "/"), true)
+	}
+
+	if !isIgnored {
+		isIgnored = this.isMatched(path, isDirectory)
+	}
+
+	return isIgnored
+}
+
+// Does the last pattern which matches the path ignore it ?
+func (this *CopyrightIgnore) isMatched(path string, isDirectory bool) bool {
+	isIgnored := false
+	for _, rule := range this.rules {
+		if rule.MatchesPath(path, isDirectory) {
+			isIgnored = !rule.IsNegated()
+		}
+	}
+	return isIgnored
+}
+
+func parseIgnoreRules(content string) ([]*Glob, error) {
+	var err error = nil
+	rules := make([]*Glob, 0)
+
+	for index, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		pattern := trimIgnorePatternSpaces(line)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		var rule *Glob
+		rule, err = NewGlobWithOptions(pattern, gitIgnoreGlobOptions)
+		if err != nil {
+			err = errors.New(fmt.Sprintf("line %d: bad pattern '%s'", index+1, line))
+			break
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, err
+}
+
+// Trailing spaces are ignored, unless they are escaped with a '\'.
+func trimIgnorePatternSpaces(line string) string {
+	pattern := strings.TrimRight(line, " \t")
+	if strings.HasSuffix(pattern, "\\") && len(pattern) < len(line) {
+		pattern = pattern + " "
+	}
+	return pattern
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCopyrightIgnore(t *testing.T, content string) *CopyrightIgnore {
+	files := NewFileReaderMock()
+	files.addFile(COPYRIGHT_IGNORE_FILE_PATH, content)
+	copyrightIgnore, err := NewCopyrightIgnore(files)
+	assert.Nil(t, err)
+	return copyrightIgnore
+}
+
+func TestCopyrightIgnoreWithoutFileIgnoresNothing(t *testing.T) {
+	// Given
+	copyrightIgnore, err := NewCopyrightIgnore(NewFileReaderMock())
+
+	// When..
+	isIgnored := copyrightIgnore.IsIgnored("vendor/lib.go", false)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.False(t, isIgnored)
+}
+
+func TestCopyrightIgnorePatternWithoutSlashMatchesInAnyFolder(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "# Generated parsers\n*.gen.go\n")
+
+	// When..
+	isTopIgnored := copyrightIgnore.IsIgnored("parser.gen.go", false)
+	isDeepIgnored := copyrightIgnore.IsIgnored("pkg/parser/parser.gen.go", false)
+	isOtherIgnored := copyrightIgnore.IsIgnored("pkg/parser/parser.go", false)
+
+	// Then...
+	assert.True(t, isTopIgnored)
+	assert.True(t, isDeepIgnored)
+	assert.False(t, isOtherIgnored)
+}
+
+func TestCopyrightIgnoreAnchoredPatternOnlyMatchesFromRoot(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "/build.sh\ndocs/*.js\n")
+
+	// When..
+	isRootIgnored := copyrightIgnore.IsIgnored("build.sh", false)
+	isNestedIgnored := copyrightIgnore.IsIgnored("scripts/build.sh", false)
+	isDocsIgnored := copyrightIgnore.IsIgnored("docs/theme.js", false)
+	isDeeperDocsIgnored := copyrightIgnore.IsIgnored("docs/js/theme.js", false)
+	isOtherDocsIgnored := copyrightIgnore.IsIgnored("site/docs/theme.js", false)
+
+	// Then...
+	assert.True(t, isRootIgnored)
+	assert.False(t, isNestedIgnored)
+	assert.True(t, isDocsIgnored)
+	assert.False(t, isDeeperDocsIgnored)
+	assert.False(t, isOtherDocsIgnored)
+}
+
+func TestCopyrightIgnoreDirectoryPatternMatchesEverythingInside(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "vendor/\ntestdata/\n")
+
+	// When..
+	isVendoredIgnored := copyrightIgnore.IsIgnored("vendor/github.com/lib/lib.go", false)
+	isTestDataIgnored := copyrightIgnore.IsIgnored("pkg/checks/testdata/fixture.java", false)
+	isFileNamedVendorIgnored := copyrightIgnore.IsIgnored("pkg/vendor", false)
+
+	// Then...
+	assert.True(t, isVendoredIgnored)
+	assert.True(t, isTestDataIgnored)
+	assert.False(t, isFileNamedVendorIgnored)
+}
+
+func TestCopyrightIgnoreDoubleStarMatchesAnyFolders(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "**/fixtures/*.yaml\nthird-party/**\nsrc/**/generated/**\n")
+
+	// When..
+	isFixtureIgnored := copyrightIgnore.IsIgnored("fixtures/one.yaml", false)
+	isDeepFixtureIgnored := copyrightIgnore.IsIgnored("a/b/fixtures/two.yaml", false)
+	isThirdPartyIgnored := copyrightIgnore.IsIgnored("third-party/x/y/z.c", false)
+	isGeneratedIgnored := copyrightIgnore.IsIgnored("src/main/generated/Api.java", false)
+	isSourceIgnored := copyrightIgnore.IsIgnored("src/main/java/Api.java", false)
+
+	// Then...
+	assert.True(t, isFixtureIgnored)
+	assert.True(t, isDeepFixtureIgnored)
+	assert.True(t, isThirdPartyIgnored)
+	assert.True(t, isGeneratedIgnored)
+	assert.False(t, isSourceIgnored)
+}
+
+func TestCopyrightIgnoreNegationBringsBackFiles(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "third-party/*\n!third-party/ours.js\n")
+
+	// When..
+	isTheirsIgnored := copyrightIgnore.IsIgnored("third-party/theirs.js", false)
+	isOursIgnored := copyrightIgnore.IsIgnored("third-party/ours.js", false)
+
+	// Then...
+	assert.True(t, isTheirsIgnored)
+	assert.False(t, isOursIgnored)
+}
+
+func TestCopyrightIgnoreNegationCantBringBackFileInIgnoredFolder(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "third-party/\n!third-party/ours.js\n")
+
+	// When..
+	isOursIgnored := copyrightIgnore.IsIgnored("third-party/ours.js", false)
+
+	// Then...
+	assert.True(t, isOursIgnored)
+}
+
+func TestCopyrightIgnoreEscapesAndCharacterClasses(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "\\#notes.md\n\\!important.md\nfile[0-9].txt\nlog[!a-z].sh\n")
+
+	// When..
+	isHashIgnored := copyrightIgnore.IsIgnored("#notes.md", false)
+	isBangIgnored := copyrightIgnore.IsIgnored("!important.md", false)
+	isDigitIgnored := copyrightIgnore.IsIgnored("file7.txt", false)
+	isLetterIgnored := copyrightIgnore.IsIgnored("fileA.txt", false)
+	isNegatedClassIgnored := copyrightIgnore.IsIgnored("log1.sh", false)
+	isNegatedClassLetterIgnored := copyrightIgnore.IsIgnored("logx.sh", false)
+
+	// Then...
+	assert.True(t, isHashIgnored)
+	assert.True(t, isBangIgnored)
+	assert.True(t, isDigitIgnored)
+	assert.False(t, isLetterIgnored)
+	assert.True(t, isNegatedClassIgnored)
+	assert.False(t, isNegatedClassLetterIgnored)
+}
+
+func TestCheckerFiltersIgnoredFilesBeforeFetchingThem(t *testing.T) {
+	// Given
+	copyrightIgnore := newTestCopyrightIgnore(t, "vendor/\n")
+	allFiles := []File{
+		{Filename: "vendor/lib/lib.go"},
+		{Filename: "main.go"},
+	}
+
+	// When..
+	filteredFiles := filterIgnoredFiles(allFiles, copyrightIgnore)
+
+	// Then...
+	assert.Equal(t, 1, len(filteredFiles))
+	assert.Equal(t, "main.go", filteredFiles[0].Filename)
+}
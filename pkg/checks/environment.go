@@ -0,0 +1,28 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"os"
+)
+
+type Environment interface {
+	// Gets the value of an environment variable. Returns "" if it is not set.
+	GetEnv(name string) string
+}
+
+type EnvironmentImpl struct {
+}
+
+func NewEnvironment() (Environment, error) {
+	var err error = nil
+	env := new(EnvironmentImpl)
+	return env, err
+}
+
+func (*EnvironmentImpl) GetEnv(name string) string {
+	return os.Getenv(name)
+}
@@ -0,0 +1,24 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+type EnvironmentMock struct {
+	variables map[string]string
+}
+
+func NewEnvironmentMock() *EnvironmentMock {
+	env := new(EnvironmentMock)
+	env.variables = make(map[string]string)
+	return env
+}
+
+func (this *EnvironmentMock) GetEnv(name string) string {
+	return this.variables[name]
+}
+
+func (this *EnvironmentMock) setEnv(name string, value string) {
+	this.variables[name] = value
+}
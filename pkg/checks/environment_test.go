@@ -0,0 +1,23 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanCreateEnvironment(t *testing.T) {
+	env, err := NewEnvironment()
+	assert.Nil(t, err)
+	assert.NotNil(t, env)
+}
+
+func TestEnvironmentMockReturnsBlankForUnsetVariable(t *testing.T) {
+	env := NewEnvironmentMock()
+	assert.Equal(t, "", env.GetEnv("NOT_SET"))
+}
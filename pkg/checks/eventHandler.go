@@ -12,6 +12,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
@@ -25,15 +26,29 @@ type EventHandlerImpl struct {
 	checker       Checker
 	tokenSupplier TokenSupplier
 	gitHubClient  GitHubClient
+
+	// The secret shared with github, used to verify that inbound webhooks really came from github.
+	webhookSecret []byte
 }
 
-func NewEventHandlerImpl(gitHubClient GitHubClient, checker Checker, tokenSupplier TokenSupplier) (EventHandler, error) {
+func NewEventHandlerImpl(gitHubClient GitHubClient, checker Checker, tokenSupplier TokenSupplier, webhookSecretFilePath string) (EventHandler, error) {
 	var err error = nil
 	this := new(EventHandlerImpl)
 	this.checker = checker
 	this.tokenSupplier = tokenSupplier
 	this.gitHubClient = gitHubClient
 
+	log.Printf("Using webhook secret file %s", webhookSecretFilePath)
+
+	var secretBytes []byte
+	secretBytes, err = os.ReadFile(webhookSecretFilePath)
+	if err == nil {
+		this.webhookSecret = []byte(strings.TrimSpace(string(secretBytes)))
+		if len(this.webhookSecret) == 0 {
+			err = errors.New(fmt.Sprintf("Webhook secret file %s is empty. A webhook secret is required.", webhookSecretFilePath))
+		}
+	}
+
 	return this, err
 }
 
@@ -43,14 +58,16 @@ func (this *EventHandlerImpl) HandleEvent(w http.ResponseWriter, r *http.Request
 
 	status, webhook := this.extractWebHook(r)
 
-	log.Printf("    Received action %v\n", webhook.Action)
+	if status == http.StatusOK {
+		log.Printf("    Received action %v\n", webhook.Action)
 
-	if webhook.CheckSuite != nil {
-		go this.performCheckSuite(&webhook)
-	} else if webhook.CheckRun != nil {
-		go this.performCheckRun(&webhook)
-	} else if webhook.Action == "opened" || webhook.Action == "synchronize" {
-		go this.performPullRequest(&webhook)
+		if webhook.CheckSuite != nil {
+			go this.performCheckSuite(&webhook)
+		} else if webhook.CheckRun != nil {
+			go this.performCheckRun(&webhook)
+		} else if webhook.Action == "opened" || webhook.Action == "synchronize" {
+			go this.performPullRequest(&webhook)
+		}
 	}
 
 	w.WriteHeader(status)
@@ -85,12 +102,19 @@ func (this *EventHandlerImpl) extractWebHook(r *http.Request) (status int, webho
 					status = http.StatusInternalServerError
 				} else {
 
-					this.gitHubClient.LogHttpPayload(jsonBytes)
+					signature := r.Header.Get(WEBHOOK_SIGNATURE_HEADER)
+					if !isWebhookSignatureValid(jsonBytes, signature, this.webhookSecret) {
+						log.Printf("Failed: Unauthorized. Request has a missing or invalid %s header.", WEBHOOK_SIGNATURE_HEADER)
+						status = http.StatusUnauthorized
+					} else {
 
-					err = json.Unmarshal(jsonBytes, &webhook)
-					if err != nil {
-						log.Printf("Parse webhook failed - %v\n", err)
-						status = http.StatusInternalServerError
+						this.gitHubClient.LogHttpPayload(jsonBytes)
+
+						err = json.Unmarshal(jsonBytes, &webhook)
+						if err != nil {
+							log.Printf("Parse webhook failed - %v\n", err)
+							status = http.StatusInternalServerError
+						}
 					}
 				}
 			}
@@ -237,24 +261,28 @@ func (this *EventHandlerImpl) performPullRequest(webhook *Webhook) error {
 func (this *EventHandlerImpl) performPullRequestChecks(webhook *Webhook, checkId int, checkRunURL string, pullRequests *[]WebhookPullRequest) *[]checkTypes.CheckError {
 
 	checkErrors := make([]checkTypes.CheckError, 0)
+	skippedFiles := make([]checkTypes.SkippedFile, 0)
+	fatalError := ""
 
 	for _, pr := range *pullRequests {
 		var err error
 		var newCheckErrors []checkTypes.CheckError
-		newCheckErrors, err = this.checkPullRequest(webhook, checkId, pr.Url)
+		var newSkippedFiles []checkTypes.SkippedFile
+		newCheckErrors, newSkippedFiles, err = this.checkPullRequest(webhook, checkId, &pr)
 		if err != nil {
 			log.Printf("(%v) Fatal error - %v", checkId, err)
-			fatalError := fmt.Sprintf("Fatal error - %v", err)
-			this.gitHubClient.UpdateCheckRun(this.tokenSupplier, webhook, checkRunURL, checkErrors, fatalError)
+			fatalError = fmt.Sprintf("Fatal error - %v", err)
 		}
 		if newCheckErrors != nil {
 			for _, newError := range newCheckErrors {
 				checkErrors = append(checkErrors, newError)
 			}
 		}
+		skippedFiles = append(skippedFiles, newSkippedFiles...)
 	}
 
-	this.gitHubClient.UpdateCheckRun(this.tokenSupplier, webhook, checkRunURL, checkErrors, "")
+	// A fatal error fails the check run, so don't overwrite it with a success afterwards.
+	this.gitHubClient.UpdateCheckRun(this.tokenSupplier, webhook, checkRunURL, checkErrors, skippedFiles, fatalError)
 
 	return &checkErrors
 }
@@ -275,10 +303,20 @@ func (this *EventHandlerImpl) performBeforeAfterChecks(
 		var filesURL string
 		filesURL, err = this.calculateFilesUrl(webhook, checkId, checkRunURL, before, after)
 
-		checkErrors, err = this.checker.CheckFilesChanged(token, filesURL)
+		var policy *Policy
+		policy, err = this.getPolicy(token, webhook, after)
+		if err != nil {
+			this.setAdhocError(webhook, checkId, checkRunURL, fmt.Sprintf("Fatal error - %v", err))
+		} else {
+
+			files := NewGitHubFileReader(this.gitHubClient, token, &webhook.Repository, after)
 
-		if err == nil {
-			this.gitHubClient.UpdateCheckRun(this.tokenSupplier, webhook, checkRunURL, checkErrors, "")
+			var skippedFiles []checkTypes.SkippedFile
+			checkErrors, skippedFiles, err = this.checker.CheckFilesChanged(token, filesURL, policy, files)
+
+			if err == nil {
+				this.gitHubClient.UpdateCheckRun(this.tokenSupplier, webhook, checkRunURL, checkErrors, skippedFiles, "")
+			}
 		}
 	}
 
@@ -288,7 +326,7 @@ func (this *EventHandlerImpl) performBeforeAfterChecks(
 func (this *EventHandlerImpl) setAdhocError(webhook *Webhook, checkId int, checkRunURL string, message string) {
 	log.Printf("(%v) %v", checkId, message)
 
-	this.gitHubClient.UpdateCheckRun(this.tokenSupplier, webhook, checkRunURL, nil, message)
+	this.gitHubClient.UpdateCheckRun(this.tokenSupplier, webhook, checkRunURL, nil, nil, message)
 }
 
 func (this *EventHandlerImpl) calculateFilesUrl(webhook *Webhook, checkId int, checkRunURL string, before string, after string) (string, error) {
@@ -315,27 +353,156 @@ func (this *EventHandlerImpl) calculateFilesUrl(webhook *Webhook, checkId int, c
 	return filesURL, err
 }
 
-func (this *EventHandlerImpl) checkPullRequest(webhook *Webhook, checkId int, pullRequestUrl string) ([]checkTypes.CheckError, error) {
-	log.Printf("(%v) Checking pullrequest '%v'", checkId, pullRequestUrl)
+func (this *EventHandlerImpl) checkPullRequest(webhook *Webhook, checkId int, pullRequest *WebhookPullRequest) ([]checkTypes.CheckError, []checkTypes.SkippedFile, error) {
+	log.Printf("(%v) Checking pullrequest '%v'", checkId, pullRequest.Url)
 
 	var err error = nil
 	installationId := webhook.Installation.Id
 
 	var checkErrors []checkTypes.CheckError = nil
+	var skippedFiles []checkTypes.SkippedFile = nil
 
 	var token string
 	token, err = this.tokenSupplier.GetToken(installationId)
 
+	if err == nil {
+
+		var policy *Policy
+		policy, err = this.getPolicy(token, webhook, pullRequest.Head.Sha)
+		if err == nil {
+
+			files := NewGitHubFileReader(this.gitHubClient, token, &webhook.Repository, pullRequest.Head.Sha)
+
+			// The files changed have the patches which suggestions need too, so only get them once.
+			var filesChanged []File
+			filesChanged, err = this.gitHubClient.GetFilesChanged(token, pullRequest.Url)
+			if err == nil {
+				checkErrors, skippedFiles, err = this.checker.CheckFiles(token, filesChanged, policy, files)
+			}
+
+			if err == nil {
+				if len(checkErrors) < 1 {
+					return nil, skippedFiles, nil
+				}
+
+				isFixPushed := false
+				if policy.IsAutoFixEnabled() {
+					isFixPushed = this.pushFixes(token, webhook, checkId, pullRequest, checkErrors)
+				}
+
+				if !isFixPushed && policy.IsSuggestionsEnabled() {
+					this.suggestFixes(token, webhook, checkId, pullRequest, checkErrors, filesChanged)
+				}
+			}
+		}
+	}
+
+	return checkErrors, skippedFiles, err
+}
+
+// Pushes a commit which fixes the headers of the files which failed to the pull request branch.
+// We can only push to branches in the same repository, not to forks.
+// Returns true if a commit was pushed.
+func (this *EventHandlerImpl) pushFixes(token string, webhook *Webhook, checkId int, pullRequest *WebhookPullRequest, checkErrors []checkTypes.CheckError) bool {
+	isFixPushed := false
+
+	if pullRequest.Head.Repo.Id != pullRequest.Base.Repo.Id {
+		log.Printf("(%v) Not fixing pull request %v, as it comes from a fork", checkId, pullRequest.Number)
+	} else {
+
+		fixedContentsByPath := make(map[string]string)
+		for _, checkError := range checkErrors {
+			if checkError.Fix != nil {
+				fixedContentsByPath[checkError.Path] = checkError.Fix.FixedContent
+			}
+		}
+
+		if len(fixedContentsByPath) > 0 {
+			message := fmt.Sprintf("Fix copyright headers\n\nCorrected the copyright headers of %d file(s) automatically.", len(fixedContentsByPath))
+
+			commitSha, err := this.gitHubClient.CommitFiles(token, &webhook.Repository, pullRequest.Head.Ref, pullRequest.Head.Sha, message, fixedContentsByPath)
+			if err != nil {
+				log.Printf("(%v) Failed to push fixes to branch %s. Reason: %s\n", checkId, pullRequest.Head.Ref, err.Error())
+			} else {
+				log.Printf("(%v) Pushed commit %s to branch %s, fixing %d file(s)\n", checkId, commitSha, pullRequest.Head.Ref, len(fixedContentsByPath))
+				isFixPushed = true
+			}
+		}
+	}
+
+	return isFixPushed
+}
+
+// Marks the reviews we make with suggestions, so we can tell them from anyone else's.
+// Github doesn't show html comments in the body of a review.
+const SUGGESTIONS_REVIEW_MARKER = "<!-- copyright-check-suggestions -->"
+
+// Reviews the pull request, with a suggested change for each file we know how to fix,
+// so the author can accept the fixes with "Commit suggestion".
+// The patches of the files changed say which lines we are allowed to comment on.
+//
+// The check runs again whenever it is re-requested, so the pull request is only reviewed once for each head commit.
+func (this *EventHandlerImpl) suggestFixes(token string, webhook *Webhook, checkId int, pullRequest *WebhookPullRequest, checkErrors []checkTypes.CheckError, filesChanged []File) {
+
+	isReviewed, err := this.isSuggestionsReviewed(token, webhook, pullRequest)
+	if err == nil && isReviewed {
+		log.Printf("(%v) Not reviewing pull request %v again, as it already has suggested fixes for %s\n", checkId, pullRequest.Number, pullRequest.Head.Sha)
+	} else if err == nil {
+
+		comments := buildSuggestionComments(checkErrors, filesChanged)
+		if len(comments) > 0 {
+			review := PullRequestReview{
+				CommitId: pullRequest.Head.Sha,
+				Body:     fmt.Sprintf("%s\nThe copyright check found problems with %d file(s). Suggested fixes are on the files below.", SUGGESTIONS_REVIEW_MARKER, len(comments)),
+				Event:    "COMMENT",
+				Comments: comments,
+			}
+			err = this.gitHubClient.CreatePullRequestReview(token, &webhook.Repository, pullRequest.Number, &review)
+			if err == nil {
+				log.Printf("(%v) Reviewed pull request %v with %d suggested fix(es)\n", checkId, pullRequest.Number, len(comments))
+			}
+		}
+	}
+
 	if err != nil {
+		log.Printf("(%v) Failed to suggest fixes on pull request %v. Reason: %s\n", checkId, pullRequest.Number, err.Error())
+	}
+}
 
-		checkErrors, err = this.checker.CheckFilesChanged(token, pullRequestUrl)
+// Has the pull request already been reviewed with our suggestions, for the commit at the head of it ?
+func (this *EventHandlerImpl) isSuggestionsReviewed(token string, webhook *Webhook, pullRequest *WebhookPullRequest) (bool, error) {
+	isReviewed := false
 
-		if err == nil {
-			if len(checkErrors) < 1 {
-				return nil, nil
+	reviews, err := this.gitHubClient.GetPullRequestReviews(token, &webhook.Repository, pullRequest.Number)
+	if err == nil {
+		for _, review := range reviews {
+			if review.CommitId == pullRequest.Head.Sha && strings.HasPrefix(review.Body, SUGGESTIONS_REVIEW_MARKER) {
+				isReviewed = true
+				break
 			}
 		}
 	}
 
-	return checkErrors, err
+	return isReviewed, err
+}
+
+// Gets the policy for the repository, from the policy file as it is at the commit being checked.
+// Repositories without a policy file get the default policy.
+func (this *EventHandlerImpl) getPolicy(token string, webhook *Webhook, headSha string) (*Policy, error) {
+	var err error = nil
+	var policy *Policy
+
+	var content string
+	var isFound bool
+	content, isFound, err = this.gitHubClient.GetRepositoryFileContent(token, &webhook.Repository, POLICY_FILE_PATH, headSha)
+	if err == nil {
+		if isFound {
+			log.Printf("Using policy file %s from %s at %s\n", POLICY_FILE_PATH, webhook.Repository.FullName, headSha)
+			policy, err = NewPolicyFromYaml(content)
+		} else {
+			policy = NewDefaultPolicy()
+		}
+	}
+
+	return policy, err
 }
@@ -0,0 +1,76 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWebhookRequest(payload string, signature string) *http.Request {
+	req := httptest.NewRequest("POST", "/githubapp/copyright/event_handler", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set(WEBHOOK_SIGNATURE_HEADER, signature)
+	}
+	return req
+}
+
+func signTestWebhookPayload(payload string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return WEBHOOK_SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestCorrectlySignedWebhookIsAccepted(t *testing.T) {
+	// Given
+	handler := &EventHandlerImpl{
+		webhookSecret: []byte(testWebhookSecret),
+		gitHubClient:  NewGitHubClient(DEFAULT_GITHUB_API_URL, DEFAULT_GITHUB_UPLOAD_URL, false),
+	}
+	payload := "{\"action\":\"closed\"}"
+	req := newTestWebhookRequest(payload, signTestWebhookPayload(payload, testWebhookSecret))
+	recorder := httptest.NewRecorder()
+
+	// When..
+	handler.HandleEvent(recorder, req)
+
+	// Then...
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestUnsignedWebhookIsRejectedAsUnauthorized(t *testing.T) {
+	// Given
+	handler := &EventHandlerImpl{webhookSecret: []byte(testWebhookSecret)}
+	req := newTestWebhookRequest(testWebhookPayload, "")
+	recorder := httptest.NewRecorder()
+
+	// When..
+	handler.HandleEvent(recorder, req)
+
+	// Then...
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestWebhookWithBadSignatureIsRejectedAsUnauthorized(t *testing.T) {
+	// Given
+	handler := &EventHandlerImpl{webhookSecret: []byte(testWebhookSecret)}
+	req := newTestWebhookRequest("{\"action\":\"opened\"}", testWebhookSignature)
+	recorder := httptest.NewRecorder()
+
+	// When..
+	handler.HandleEvent(recorder, req)
+
+	// Then...
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -7,31 +7,25 @@ package checks
 
 import (
 	"log"
-	"regexp"
 
 	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
 	"github.com/galasa-dev/githubapp-copyright/pkg/fileCheckers"
 )
 
 type Checker interface {
-	CheckFilesChanged(token string, url string) ([]checkTypes.CheckError, error)
+	// Checks the files changed by a commit or pull request.
+	// The file reader gets other files from the same commit, such as REUSE .license files.
+	// Returns the problems found, and the files which were skipped because they are binary or generated.
+	CheckFilesChanged(token string, url string, policy *Policy, files FileReader) ([]checkTypes.CheckError, []checkTypes.SkippedFile, error)
 
-	CheckFile(token string, file *File) *checkTypes.CheckError
+	// The same as CheckFilesChanged, for a list of files changed which has already been got from github.
+	CheckFiles(token string, allFiles []File, policy *Policy, files FileReader) ([]checkTypes.CheckError, []checkTypes.SkippedFile, error)
+
+	// Returns a problem with the file, or why it was skipped. Both are nil if the file is fine.
+	CheckFile(token string, file *File, policy *Policy, classifier *FileClassifier) (*checkTypes.CheckError, *checkTypes.SkippedFile)
 }
 
 type CheckerImpl struct {
-	javaCommentBlockPattern *regexp.Regexp
-
-	//licencePattern   *regexp.Regexp
-	javaCopyrightPattern         *regexp.Regexp
-	hashCopyrightPattern         *regexp.Regexp
-	javaExpectedCopyrightMessage string
-	hashExpectedCopyrightMessage string
-
-	// The index is the file extension (including the dot) eg: ".java"
-	// The value is the file checker which will be used.
-	checkersByExtension map[string]fileCheckers.FileChecker
-
 	gitHubClient GitHubClient
 }
 
@@ -42,97 +36,234 @@ func NewChecker(client GitHubClient) (Checker, error) {
 	checker := new(CheckerImpl)
 
 	checker.gitHubClient = client
-	checker.javaCommentBlockPattern = regexp.MustCompile(`\s*\/[*]((.|\s)*)[*]\/`)
-
-	// \s means any whitespace character (including \n new lines)
-	// [*] means a splat/star/asterisk character.
-	// We are trying to all this:
-	// A copyright message "Copyright contributors to the Galasa project" followed by
-	// any number of lines with leading and trailing whitespace around an asterisk, followed by
-	// a line containing <optional-whitespace>SPDX-License-Identifier:<optional-whitespace>EPL-2.0
-	checker.javaCopyrightPattern = regexp.MustCompile(`Copyright contributors to the Galasa project(\s*[*]\s*)*\s*[*]\s*SPDX-License-Identifier:\s*EPL-2[.]0`)
-	checker.hashCopyrightPattern = regexp.MustCompile(`Copyright contributors to the Galasa project(\s*[#]\s*)*\s*[#]\s*SPDX-License-Identifier:\s*EPL-2[.]0`)
-
-	checker.javaExpectedCopyrightMessage = "\nExpected to see:\n/*\n * Copyright contributors to the Galasa project\n *\n * SPDX-License-Identifier: EPL-2.0\n */"
-	checker.hashExpectedCopyrightMessage = "\nExpected to see:\n#\n# Copyright contributors to the Galasa project\n#\n# SPDX-License-Identifier: EPL-2.0\n#"
-
-	var javaChecker fileCheckers.FileChecker
-	javaChecker = fileCheckers.NewJavaFileChecker()
-
-	var yamlChecker fileCheckers.FileChecker
-	yamlChecker = fileCheckers.NewYamlFileChecker()
-
-	checker.checkersByExtension = map[string]fileCheckers.FileChecker{
-		".java": javaChecker,
-		".go":   javaChecker,
-		".ts":   javaChecker,
-		".tsx":  javaChecker,
-		".js":   javaChecker,
-		".yaml": yamlChecker,
-		".sh":   yamlChecker,
-	}
 
 	return checker, err
 }
 
-func (this *CheckerImpl) CheckFilesChanged(token string, url string) ([]checkTypes.CheckError, error) {
-	var allFiles []File
+func (this *CheckerImpl) CheckFilesChanged(token string, url string, policy *Policy, files FileReader) ([]checkTypes.CheckError, []checkTypes.SkippedFile, error) {
+	var checkErrors []checkTypes.CheckError = nil
+	var skippedFiles []checkTypes.SkippedFile = nil
+
+	allFiles, err := this.gitHubClient.GetFilesChanged(token, url)
+	if err == nil {
+		checkErrors, skippedFiles, err = this.CheckFiles(token, allFiles, policy, files)
+	}
+
+	return checkErrors, skippedFiles, err
+}
+
+func (this *CheckerImpl) CheckFiles(token string, allFiles []File, policy *Policy, files FileReader) ([]checkTypes.CheckError, []checkTypes.SkippedFile, error) {
 	var err error = nil
 
 	var checkErrors []checkTypes.CheckError = make([]checkTypes.CheckError, 0)
+	var skippedFiles []checkTypes.SkippedFile = make([]checkTypes.SkippedFile, 0)
 
-	allFiles, err = this.gitHubClient.GetFilesChanged(token, url)
+	var reuse *Reuse
+	reuse, err = NewReuse(files, policy)
+
+	var classifier *FileClassifier
+	if err == nil {
+		classifier, err = NewFileClassifier(files)
+	}
+
+	var copyrightIgnore *CopyrightIgnore
+	if err == nil {
+		copyrightIgnore, err = NewCopyrightIgnore(files)
+	}
+
+	if err == nil {
+		allFiles = filterIgnoredFiles(allFiles, copyrightIgnore)
+	} else {
+		// Don't check anything if the REUSE, .gitattributes or .copyrightignore files are wrong.
+		allFiles = nil
+	}
 
 	for _, file := range allFiles {
 		var newCheckError *checkTypes.CheckError
-		newCheckError = this.CheckFile(token, &file)
+		var skippedFile *checkTypes.SkippedFile
+		newCheckError, skippedFile = this.CheckFile(token, &file, policy, classifier)
+		if skippedFile != nil {
+			skippedFiles = append(skippedFiles, *skippedFile)
+		}
+		if newCheckError != nil {
+			newCheckError = reuse.ResolveCheckError(newCheckError)
+		}
 
 		if newCheckError != nil {
 			log.Printf("Found problem with file %v - %v", file.Filename, newCheckError.Message)
-			checkErrors = append(checkErrors, *newCheckError)
+			checkErrors = appendCheckError(checkErrors, newCheckError)
 		}
 
 		// Continue to check the next file also.
 	}
-	return checkErrors, err
+	return checkErrors, skippedFiles, err
 
 }
 
-func (this *CheckerImpl) CheckFile(token string, file *File) *checkTypes.CheckError {
-
-	var err error = nil
-	var checkError *checkTypes.CheckError
+func (this *CheckerImpl) CheckFile(token string, file *File, policy *Policy, classifier *FileClassifier) (*checkTypes.CheckError, *checkTypes.SkippedFile) {
 
 	// we dont care about deleted files
 	if file.Status == "removed" {
-		return nil
+		return nil, nil
 	}
 
-	fileExtension := extractFileExtension(file.Filename)
+	return checkFileWithPolicy(file.Filename, policy, classifier, func() (string, error) {
+		return this.gitHubClient.GetFileContentFromGithub(token, file)
+	})
+}
+
+// Reads the content of a file which is being checked.
+type contentReader func() (string, error)
 
-	// Decide which file checker we want to use.
-	fileChecker, isExtensionRecognised := this.checkersByExtension[fileExtension]
+// Checks a file in the way the policy says, wherever the file comes from.
+// The content is only read if it is needed, as it can mean a call to github.
+// Returns a problem with the file, or why it was skipped. Both are nil if the file is fine, or isn't checked.
+func checkFileWithPolicy(path string, policy *Policy, classifier *FileClassifier, readContent contentReader) (*checkTypes.CheckError, *checkTypes.SkippedFile) {
+	var checkError *checkTypes.CheckError = nil
+	var skippedFile *checkTypes.SkippedFile = nil
 
-	if !isExtensionRecognised {
-		// Don't bother getting the file if we don't know how to check it for copyright.
-		log.Printf("File file %s is not checked because extension %s is not checked for copyright.\n", file.Filename, fileExtension)
+	if !policy.IsFileIncluded(path) {
+		log.Printf("File %s is not checked because the repository policy excludes it.\n", path)
 	} else {
 
-		var fileContent string
-		fileContent, err = this.gitHubClient.GetFileContentFromGithub(token, file)
-		if err == nil {
+		// Decide which file checker we want to use.
+		fileChecker := policy.GetFileChecker(path)
+
+		if fileChecker == nil && !policy.IsPossibleScript(path) {
+			// Don't bother getting the file if we don't know how to check it for copyright.
+			log.Printf("File %s is not checked because extension %s is not checked for copyright.\n", path, extractFileExtension(path))
+		} else if reason := classifier.GetSkipReasonForPath(path); reason != "" {
+			skippedFile = newSkippedFile(path, reason)
+		} else if !isContentChecked(fileChecker) {
+			// Don't bother getting a file whose content can't make a difference. eg: An image which must be covered by REUSE
+			checkError = fileChecker.CheckFileContent("", path)
+		} else {
 
-			checkError = fileChecker.CheckFileContent(fileContent, file.Filename)
+			content, err := readContent()
+			if err == nil {
+
+				if fileChecker == nil {
+					// Without an extension, the #! line is all we have to go on.
+					fileChecker = policy.GetFileCheckerForScript(content)
+				}
+
+				if fileChecker == nil {
+					log.Printf("File %s is not checked because it has no extension, and is not a script which is checked for copyright.\n", path)
+				} else if reason := classifier.GetSkipReasonForContent(content); reason != "" {
+					skippedFile = newSkippedFile(path, reason)
+				} else if directive := fileCheckers.FindCheckDirective(content); directive.IsSuppressing(content) {
+					skippedFile = newSuppressedFile(path, directive)
+				} else if directiveCheckError := directive.NewCheckError(content, path); directiveCheckError != nil {
+					// The directive has to be put right before anything else about the file matters.
+					checkError = directiveCheckError
+				} else {
+					checkError = checkFileContent(fileChecker, content, path, policy.header, directive)
+				}
+			} else {
+				// Turn the error into a checker error so it fails the check.
+				log.Printf("Failed to check file %s. Reason: %s\n", path, err.Error())
+				checkError = checkTypes.NewCheckError(path, err.Error(), 0)
+			}
+		}
+	}
+
+	return checkError, skippedFile
+}
+
+// Takes out the files which the .copyrightignore file says should not be checked, so we don't fetch them.
+func filterIgnoredFiles(allFiles []File, copyrightIgnore *CopyrightIgnore) []File {
+	filteredFiles := make([]File, 0, len(allFiles))
+	for _, file := range allFiles {
+		if copyrightIgnore.IsIgnored(file.Filename, false) {
+			log.Printf("File %s is not checked because %s ignores it.\n", file.Filename, COPYRIGHT_IGNORE_FILE_PATH)
+		} else {
+			filteredFiles = append(filteredFiles, file)
+		}
+	}
+	return filteredFiles
+}
+
+// Checks the content of a file. If there is a problem the file checker knows how to fix, the fix is attached to the check error.
+// A file which fails because it has a legacy header is told exactly which lines to replace, and the fix replaces them.
+// A file which fails because it is someone else's code is reported as third-party code instead, which is never fixed.
+// The copyright-check directive already found in the file is given to file checkers which honour it. It can be nil.
+func checkFileContent(fileChecker fileCheckers.FileChecker, content string, fileName string, header fileCheckers.CopyrightHeader, directive *fileCheckers.CheckDirective) *checkTypes.CheckError {
+	var checkError *checkTypes.CheckError
+	directiveFileChecker, isDirectiveHonoured := fileChecker.(fileCheckers.DirectiveFileChecker)
+	if isDirectiveHonoured {
+		checkError = directiveFileChecker.CheckFileContentWithDirective(content, fileName, directive)
+	} else {
+		checkError = fileChecker.CheckFileContent(content, fileName)
+	}
+
+	if checkError != nil {
+		legacyHeader := fileCheckers.FindLegacyHeader(content, header)
+		if legacyHeader != nil {
+			log.Printf("File %s has a legacy header - %s\n", fileName, checkError.Message)
+			checkError = legacyHeader.NewCheckError(content, fileName)
+
+			fixedContent, err := legacyHeader.FixFileContent(fileChecker, content, fileName)
+			if err != nil {
+				// The file checker may still be able to fix it some other way.
+				log.Printf("%s\n", err.Error())
+			} else {
+				checkError.Fix = &checkTypes.FileFix{
+					OriginalContent: content,
+					FixedContent:    fixedContent,
+				}
+			}
 		} else {
-			// Turn the error into a checker error so it fails the check in github.
-			log.Printf("Failed to check file %s. Reason: %s\n", file.Filename, err.Error())
-			checkError = &checkTypes.CheckError{
-				Path:     file.Filename,
-				Message:  err.Error(),
-				Location: 0,
+			thirdPartyCheckError := fileCheckers.CheckThirdPartyCode(content, fileName, header)
+			if thirdPartyCheckError != nil {
+				log.Printf("File %s looks like third-party code - %s\n", fileName, checkError.Message)
+				checkError = thirdPartyCheckError
+			}
+		}
+	}
+
+	if checkError != nil && !checkError.IsThirdParty && checkError.Fix == nil {
+		fileFixer, isFixable := fileChecker.(fileCheckers.FileFixer)
+		if isFixable {
+			fixedContent, err := fileFixer.FixFileContent(content, fileName)
+			if err != nil {
+				log.Printf("%s\n", err.Error())
+			} else {
+				checkError.Fix = &checkTypes.FileFix{
+					OriginalContent: content,
+					FixedContent:    fixedContent,
+				}
 			}
 		}
 	}
 
 	return checkError
 }
+
+// Adds a problem to a list, unless there is already one for the same file.
+// A .license file can be reported for itself, and again for the file it covers.
+func appendCheckError(checkErrors []checkTypes.CheckError, checkError *checkTypes.CheckError) []checkTypes.CheckError {
+	for _, existingCheckError := range checkErrors {
+		if existingCheckError.Path == checkError.Path {
+			return checkErrors
+		}
+	}
+	return append(checkErrors, *checkError)
+}
+
+// Files which are skipped are reported, so people know they weren't checked.
+func newSkippedFile(path string, reason string) *checkTypes.SkippedFile {
+	log.Printf("File %s is skipped - %s\n", path, reason)
+	return checkTypes.NewSkippedFile(path, reason)
+}
+
+// Files with a copyright-check directive are reported, so reviewers can see which files opted out.
+func newSuppressedFile(path string, directive *fileCheckers.CheckDirective) *checkTypes.SkippedFile {
+	log.Printf("File %s is suppressed - %s\n", path, directive.Text)
+	return checkTypes.NewSuppressedFile(path, directive.Text)
+}
+
+// Does the file checker look at the content of a file ? Checkers for files which can't hold a header don't.
+func isContentChecked(fileChecker fileCheckers.FileChecker) bool {
+	_, isReuseOnly := fileChecker.(*fileCheckers.ReuseOnlyFileChecker)
+	return !isReuseOnly
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/fileCheckers"
+)
+
+// Where a repository can mark files as generated or vendored, for github's linguist.
+// See https://github.com/github-linguist/linguist/blob/master/docs/overrides.md
+const GIT_ATTRIBUTES_FILE_PATH = ".gitattributes"
+
+const (
+	LINGUIST_GENERATED_ATTRIBUTE = "linguist-generated"
+	LINGUIST_VENDORED_ATTRIBUTE  = "linguist-vendored"
+)
+
+// How far into a file we look for a NUL byte, which is how git decides whether a file is binary.
+const BINARY_DETECTION_LENGTH = 8000
+
+// How many lines at the top of a file we look in for signs that it was generated or minified.
+const CLASSIFICATION_LINE_COUNT = 10
+
+// Lines longer than this near the top of a file are taken to mean it is minified. eg: A webpack bundle
+const MINIFIED_LINE_LENGTH = 1000
+
+// Comments which say a file was generated by a tool.
+// eg: "// Code generated by protoc-gen-go. DO NOT EDIT." which is the convention from https://go.dev/s/generatedcode
+var generatedCodeMarkerPatterns = []*regexp.Regexp{
+	fileCheckers.GeneratedCodeLinePattern,
+	regexp.MustCompile(`@generated\b`),
+	regexp.MustCompile(`(?i)\b(auto-?)?generated\b.*\bdo not (edit|modify)\b`),
+}
+
+// File names which say a file is minified. eg: "jquery.min.js"
+var minifiedFileNamePattern = regexp.MustCompile(`\.min\.(js|mjs|cjs|css)$`)
+
+// Sets or unsets linguist attributes on the files which match a .gitattributes pattern.
+type gitAttributesRule struct {
+	glob *Glob
+
+	// Only the attributes the rule mentions are in the map.
+	attributes map[string]bool
+}
+
+// Decides whether a file should be checked at all, or is something nobody would expect to have a header.
+// eg: Binary files, generated code, minified bundles and vendored code
+type FileClassifier struct {
+	attributeRules []*gitAttributesRule
+}
+
+// Reads the .gitattributes file of a repository, if there is one.
+func NewFileClassifier(files FileReader) (*FileClassifier, error) {
+	this := new(FileClassifier)
+	this.attributeRules = make([]*gitAttributesRule, 0)
+
+	content, isFound, err := files.ReadFile(GIT_ATTRIBUTES_FILE_PATH)
+	if err == nil && isFound {
+		this.attributeRules = parseGitAttributes(content)
+	}
+
+	return this, err
+}
+
+// Gets the reason a file should be skipped, from its path alone. Returns "" if the file should be checked.
+// Checking the path first saves fetching the content of files which won't be checked.
+func (this *FileClassifier) GetSkipReasonForPath(path string) string {
+	reason := ""
+
+	isGenerated := this.getAttribute(path, LINGUIST_GENERATED_ATTRIBUTE)
+	isVendored := this.getAttribute(path, LINGUIST_VENDORED_ATTRIBUTE)
+
+	if isGenerated {
+		reason = "marked " + LINGUIST_GENERATED_ATTRIBUTE + " in " + GIT_ATTRIBUTES_FILE_PATH
+	} else if isVendored {
+		reason = "marked " + LINGUIST_VENDORED_ATTRIBUTE + " in " + GIT_ATTRIBUTES_FILE_PATH
+	} else if minifiedFileNamePattern.MatchString(path) {
+		reason = "minified file"
+	}
+
+	return reason
+}
+
+// Gets the reason a file should be skipped, from its content. Returns "" if the file should be checked.
+func (this *FileClassifier) GetSkipReasonForContent(content string) string {
+	reason := ""
+
+	start := content
+	if len(start) > BINARY_DETECTION_LENGTH {
+		start = start[:BINARY_DETECTION_LENGTH]
+	}
+
+	if strings.Contains(start, "\x00") {
+		reason = "binary content"
+	} else {
+		lines := strings.SplitN(content, "\n", CLASSIFICATION_LINE_COUNT+1)
+		if len(lines) > CLASSIFICATION_LINE_COUNT {
+			lines = lines[:CLASSIFICATION_LINE_COUNT]
+		}
+
+		for _, line := range lines {
+			line = strings.TrimRight(line, "\r")
+			if len(line) > MINIFIED_LINE_LENGTH {
+				reason = fmt.Sprintf("minified content, with a line over %d characters long", MINIFIED_LINE_LENGTH)
+			} else if isGeneratedCodeMarker(line) {
+				reason = fmt.Sprintf("generated code marker \"%s\"", strings.TrimSpace(line))
+			}
+
+			if reason != "" {
+				break
+			}
+		}
+	}
+
+	return reason
+}
+
+func isGeneratedCodeMarker(line string) bool {
+	isMarker := false
+	for _, pattern := range generatedCodeMarkerPatterns {
+		if pattern.MatchString(line) {
+			isMarker = true
+			break
+		}
+	}
+	return isMarker
+}
+
+// Works out whether an attribute is set for a path. As in git, the last rule which mentions the attribute wins.
+func (this *FileClassifier) getAttribute(path string, attribute string) bool {
+	isSet := false
+	for _, rule := range this.attributeRules {
+		value, isMentioned := rule.attributes[attribute]
+		if isMentioned && rule.glob.Matches(path) {
+			isSet = value
+		}
+	}
+	return isSet
+}
+
+// Parses the lines of a .gitattributes file which mention the linguist attributes. eg:
+//
+//	dist/** linguist-generated
+//	vendor/** linguist-vendored=true
+//	vendor/ours/** -linguist-vendored
+//
+// See https://git-scm.com/docs/gitattributes
+func parseGitAttributes(content string) []*gitAttributesRule {
+	rules := make([]*gitAttributesRule, 0)
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		fields := strings.Fields(line)
+
+		// Macro definitions start with [attr], and aren't about any files.
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[attr]") {
+			continue
+		}
+
+		rule := new(gitAttributesRule)
+		rule.attributes = make(map[string]bool)
+
+		for _, field := range fields[1:] {
+			name, value := parseGitAttribute(field)
+			if name == LINGUIST_GENERATED_ATTRIBUTE || name == LINGUIST_VENDORED_ATTRIBUTE {
+				rule.attributes[name] = value
+			}
+		}
+
+		if len(rule.attributes) > 0 && strings.HasPrefix(fields[0], "!") {
+			// As in git, negative patterns are ignored.
+			log.Printf("Ignoring negative pattern %s in %s.\n", fields[0], GIT_ATTRIBUTES_FILE_PATH)
+		} else if len(rule.attributes) > 0 {
+			var err error
+			rule.glob, err = NewGlobWithOptions(fields[0], gitAttributesGlobOptions)
+			if err != nil {
+				// It's not our file, so don't fail the check because of it.
+				log.Printf("Ignoring pattern %s in %s. Reason: %s\n", fields[0], GIT_ATTRIBUTES_FILE_PATH, err.Error())
+			} else {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	return rules
+}
+
+// Gets the name of an attribute and whether it is set. eg: "-linguist-generated" is not set,
+// "linguist-generated" and "linguist-generated=true" are set.
+func parseGitAttribute(field string) (string, bool) {
+	var name string
+	value := true
+
+	if strings.HasPrefix(field, "-") || strings.HasPrefix(field, "!") {
+		name = field[1:]
+		value = false
+	} else {
+		equals := strings.Index(field, "=")
+		if equals < 0 {
+			name = field
+		} else {
+			name = field[:equals]
+			value = strings.ToLower(field[equals+1:]) != "false"
+		}
+	}
+
+	return name, value
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifierWithoutGitAttributesChecksAllPaths(t *testing.T) {
+	// Given
+	classifier, err := NewFileClassifier(NewFileReaderMock())
+
+	// When..
+	reason := classifier.GetSkipReasonForPath("src/main.js")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "", reason)
+}
+
+func TestClassifierSkipsPathsMarkedInGitAttributes(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(GIT_ATTRIBUTES_FILE_PATH, `# Keep the language statistics honest
+*.pb.go linguist-generated=true
+dist/** linguist-generated
+third-party/** linguist-vendored
+third-party/ours/** -linguist-vendored
+docs/** linguist-documentation
+[attr]binary -diff -merge -text
+`)
+	classifier, _ := NewFileClassifier(files)
+
+	// When..
+	generatedReason := classifier.GetSkipReasonForPath("pkg/api/service.pb.go")
+	distReason := classifier.GetSkipReasonForPath("dist/bundle.js")
+	vendoredReason := classifier.GetSkipReasonForPath("third-party/lib/lib.js")
+	unvendoredReason := classifier.GetSkipReasonForPath("third-party/ours/lib.js")
+	docsReason := classifier.GetSkipReasonForPath("docs/guide.js")
+
+	// Then...
+	assert.Equal(t, "marked linguist-generated in .gitattributes", generatedReason)
+	assert.Equal(t, "marked linguist-generated in .gitattributes", distReason)
+	assert.Equal(t, "marked linguist-vendored in .gitattributes", vendoredReason)
+	assert.Equal(t, "", unvendoredReason)
+	assert.Equal(t, "", docsReason)
+}
+
+func TestClassifierMatchesGitAttributesPatternsAsGitDoes(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(GIT_ATTRIBUTES_FILE_PATH, `/build/** linguist-generated
+dist/** linguist-generated
+**/gen/*.js linguist-generated
+!src/** linguist-vendored
+`)
+	classifier, _ := NewFileClassifier(files)
+
+	// When..
+	anchoredReason := classifier.GetSkipReasonForPath("build/out.js")
+	nestedAnchoredReason := classifier.GetSkipReasonForPath("pkg/build/out.js")
+	distReason := classifier.GetSkipReasonForPath("dist/bundle.js")
+	nestedDistReason := classifier.GetSkipReasonForPath("web/dist/bundle.js")
+	nestedGenReason := classifier.GetSkipReasonForPath("web/app/gen/model.js")
+	negatedReason := classifier.GetSkipReasonForPath("src/main.js")
+
+	// Then...
+	assert.Equal(t, "marked linguist-generated in .gitattributes", anchoredReason)
+	assert.Equal(t, "", nestedAnchoredReason)
+	assert.Equal(t, "marked linguist-generated in .gitattributes", distReason)
+	assert.Equal(t, "", nestedDistReason)
+	assert.Equal(t, "marked linguist-generated in .gitattributes", nestedGenReason)
+	assert.Equal(t, "", negatedReason)
+}
+
+func TestClassifierSkipsMinifiedFileNames(t *testing.T) {
+	// Given
+	classifier, _ := NewFileClassifier(NewFileReaderMock())
+
+	// When..
+	reason := classifier.GetSkipReasonForPath("static/jquery.min.js")
+
+	// Then...
+	assert.Equal(t, "minified file", reason)
+}
+
+func TestClassifierSkipsContentWithNulBytes(t *testing.T) {
+	// Given
+	classifier, _ := NewFileClassifier(NewFileReaderMock())
+
+	// When..
+	reason := classifier.GetSkipReasonForContent("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	// Then...
+	assert.Equal(t, "binary content", reason)
+}
+
+func TestClassifierSkipsGoGeneratedCode(t *testing.T) {
+	// Given
+	classifier, _ := NewFileClassifier(NewFileReaderMock())
+
+	// When..
+	reason := classifier.GetSkipReasonForContent("// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: service.proto\n\npackage api\n")
+
+	// Then...
+	assert.Equal(t, `generated code marker "// Code generated by protoc-gen-go. DO NOT EDIT."`, reason)
+}
+
+func TestClassifierSkipsOtherGeneratedCodeMarkers(t *testing.T) {
+	// Given
+	classifier, _ := NewFileClassifier(NewFileReaderMock())
+
+	// When..
+	atGeneratedReason := classifier.GetSkipReasonForContent("/**\n * @generated\n */\nclass Foo {}\n")
+	autoGeneratedReason := classifier.GetSkipReasonForContent("# This file is auto-generated by openapi-generator. Do not edit it by hand.\nkey: value\n")
+
+	// Then...
+	assert.Contains(t, atGeneratedReason, "generated code marker")
+	assert.Contains(t, autoGeneratedReason, "generated code marker")
+}
+
+func TestClassifierSkipsMinifiedContent(t *testing.T) {
+	// Given
+	classifier, _ := NewFileClassifier(NewFileReaderMock())
+
+	// When..
+	reason := classifier.GetSkipReasonForContent("/*! bundle */\n!function(e){" + strings.Repeat("var a=1;", 200) + "}();\n")
+
+	// Then...
+	assert.Contains(t, reason, "minified content")
+}
+
+func TestClassifierChecksOrdinaryContent(t *testing.T) {
+	// Given
+	classifier, _ := NewFileClassifier(NewFileReaderMock())
+
+	// When..
+	reason := classifier.GetSkipReasonForContent(goodJavaContent + "\n// We don't want the generated code here.\n")
+
+	// Then...
+	assert.Equal(t, "", reason)
+}
@@ -12,10 +12,18 @@ import (
 func extractFileExtension(fileName string) string {
 	fileExtension := ""
 
-	indexOfLastDot := strings.LastIndex(fileName, ".")
+	// Only look in the last part of the path, so a dot in a folder name isn't mistaken for an extension.
+	baseFileName := extractBaseFileName(fileName)
+
+	indexOfLastDot := strings.LastIndex(baseFileName, ".")
 	if indexOfLastDot >= 0 {
-		fileExtension = fileName[indexOfLastDot:]
+		fileExtension = baseFileName[indexOfLastDot:]
 	}
 
 	return fileExtension
 }
+
+// Gets the name of the file without the folders it is in. eg: "Dockerfile" for "images/base/Dockerfile"
+func extractBaseFileName(fileName string) string {
+	return fileName[strings.LastIndex(fileName, "/")+1:]
+}
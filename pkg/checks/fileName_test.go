@@ -25,3 +25,13 @@ func TestIgnoresMultipleDotsAndGetsRealFileExtension(t *testing.T) {
 	fileExtension := extractFileExtension("myPackage.myClassFile.java")
 	assert.Equal(t, fileExtension, ".java")
 }
+
+func TestDotInFolderNameIsNotAnExtension(t *testing.T) {
+	fileExtension := extractFileExtension("images/base.image/Dockerfile")
+	assert.Equal(t, fileExtension, "")
+}
+
+func TestCanExtractBaseFileName(t *testing.T) {
+	assert.Equal(t, "Dockerfile", extractBaseFileName("images/base/Dockerfile"))
+	assert.Equal(t, "Makefile", extractBaseFileName("Makefile"))
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Reads other files from the commit or folder being checked. eg: The .license file next to an image
+type FileReader interface {
+	// Gets the content of a file. The path is relative to the root of the repository, using '/' separators.
+	// isFound is false if there is no such file, which is not an error.
+	ReadFile(path string) (content string, isFound bool, err error)
+}
+
+// Reads files from a commit in a github repository.
+type GitHubFileReader struct {
+	gitHubClient GitHubClient
+	token        string
+	repository   *WebhookRepository
+	ref          string
+}
+
+func NewGitHubFileReader(gitHubClient GitHubClient, token string, repository *WebhookRepository, ref string) FileReader {
+	this := new(GitHubFileReader)
+	this.gitHubClient = gitHubClient
+	this.token = token
+	this.repository = repository
+	this.ref = ref
+	return this
+}
+
+func (this *GitHubFileReader) ReadFile(path string) (string, bool, error) {
+	return this.gitHubClient.GetRepositoryFileContent(this.token, this.repository, path, this.ref)
+}
+
+// Reads files from a folder on the local file system.
+type LocalFileReader struct {
+	directory string
+}
+
+func NewLocalFileReader(directory string) FileReader {
+	this := new(LocalFileReader)
+	this.directory = directory
+	return this
+}
+
+func (this *LocalFileReader) ReadFile(path string) (string, bool, error) {
+	var err error = nil
+	var content string
+	isFound := false
+
+	var contentBytes []byte
+	contentBytes, err = os.ReadFile(filepath.Join(this.directory, filepath.FromSlash(path)))
+	if err == nil {
+		content = string(contentBytes)
+		isFound = true
+	} else if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+
+	return content, isFound, err
+}
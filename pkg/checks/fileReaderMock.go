@@ -0,0 +1,25 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+type FileReaderMock struct {
+	contentsByPath map[string]string
+}
+
+func NewFileReaderMock() *FileReaderMock {
+	this := new(FileReaderMock)
+	this.contentsByPath = make(map[string]string)
+	return this
+}
+
+func (this *FileReaderMock) ReadFile(path string) (string, bool, error) {
+	content, isFound := this.contentsByPath[path]
+	return content, isFound, nil
+}
+
+func (this *FileReaderMock) addFile(path string, content string) {
+	this.contentsByPath[path] = content
+}
@@ -14,28 +14,43 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
 )
 
 type GitHubClient interface {
-	UpdateCheckRun(tokenSupplier TokenSupplier, webhook *Webhook, checkRunURL string, checkErrors []checkTypes.CheckError, fatalError string) error
+	UpdateCheckRun(tokenSupplier TokenSupplier, webhook *Webhook, checkRunURL string, checkErrors []checkTypes.CheckError, skippedFiles []checkTypes.SkippedFile, fatalError string) error
 	GetFilesChanged(token string, baseUrl string) ([]File, error)
 	GetFileContentFromGithub(token string, file *File) (string, error)
+	GetRepositoryFileContent(token string, repository *WebhookRepository, path string, ref string) (content string, isFound bool, err error)
 	CreateCheckRun(tokenSupplier TokenSupplier, webhook *Webhook, headSha string) (string, error)
-	GetNewToken(accessUrl string, githubAuthToken string) (tokenResponse InstallationToken, err error)
+	CreatePullRequestReview(token string, repository *WebhookRepository, pullRequestNumber int, review *PullRequestReview) error
+	GetPullRequestReviews(token string, repository *WebhookRepository, pullRequestNumber int) ([]PullRequestReview, error)
+	CommitFiles(token string, repository *WebhookRepository, branch string, parentSha string, message string, contentsByPath map[string]string) (string, error)
+	GetNewToken(installationId int, githubAuthToken string) (tokenResponse InstallationToken, err error)
 	LogHttpPayload(jsonBytes []byte)
 }
 
 type GitHubClientImpl struct {
 	httpClient          *http.Client
 	isHttpTrafficLogged bool
+
+	// All the REST API URLs we build ourselves are relative to this. eg: https://api.github.com
+	apiBaseUrl string
+
+	// The base URL for uploads. eg: https://uploads.github.com
+	uploadBaseUrl string
 }
 
-func NewGitHubClient(isHttpTrafficLogged bool) GitHubClient {
+func NewGitHubClient(apiBaseUrl string, uploadBaseUrl string, isHttpTrafficLogged bool) GitHubClient {
 	this := new(GitHubClientImpl)
 	this.httpClient = &http.Client{}
 	this.isHttpTrafficLogged = isHttpTrafficLogged
+	this.apiBaseUrl = strings.TrimSuffix(apiBaseUrl, "/")
+	this.uploadBaseUrl = strings.TrimSuffix(uploadBaseUrl, "/")
 	return this
 }
 
@@ -45,7 +60,9 @@ func (this *GitHubClientImpl) LogHttpPayload(jsonBytes []byte) {
 	}
 }
 
-func (this *GitHubClientImpl) GetNewToken(accessUrl string, githubAuthToken string) (tokenResponse InstallationToken, err error) {
+func (this *GitHubClientImpl) GetNewToken(installationId int, githubAuthToken string) (tokenResponse InstallationToken, err error) {
+
+	accessUrl := fmt.Sprintf("%s/app/installations/%v/access_tokens", this.apiBaseUrl, installationId)
 
 	var req *http.Request
 	req, err = http.NewRequest("POST", accessUrl, nil)
@@ -166,8 +183,40 @@ func (this *GitHubClientImpl) GetFileContentFromGithub(token string, file *File)
 	return content, err
 }
 
+// Gets the content of any file in a repository, as it was at the given commit sha or branch.
+// If the file doesn't exist, isFound is false, but that isn't an error.
+func (this *GitHubClientImpl) GetRepositoryFileContent(token string, repository *WebhookRepository, path string, ref string) (string, bool, error) {
+	contentURL := fmt.Sprintf("%s/repos/%s/contents/%s?ref=%s", this.apiBaseUrl, repository.FullName, escapePath(path), url.QueryEscape(ref))
+
+	content, statusCode, err := this.getRawContent(token, contentURL)
+
+	isFound := true
+	if statusCode == http.StatusNotFound {
+		isFound = false
+		err = nil
+	}
+
+	return content, isFound, err
+}
+
+// Escapes each segment of a file path, so names with characters such as '#', '?', '%' or spaces can go in a URL.
+// The '/' between the segments are left as they are.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for index, segment := range segments {
+		segments[index] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
 func (this *GitHubClientImpl) getFileContent(token string, contentURL string) (string, error) {
+	contents, _, err := this.getRawContent(token, contentURL)
+	return contents, err
+}
+
+func (this *GitHubClientImpl) getRawContent(token string, contentURL string) (string, int, error) {
 	contents := ""
+	statusCode := 0
 
 	var err error = nil
 	var req *http.Request
@@ -184,6 +233,7 @@ func (this *GitHubClientImpl) getFileContent(token string, contentURL string) (s
 		if err == nil {
 
 			defer resp.Body.Close()
+			statusCode = resp.StatusCode
 			if resp.StatusCode != 200 {
 				err = errors.New("invalid response from content fetch " + resp.Status)
 			} else {
@@ -200,7 +250,7 @@ func (this *GitHubClientImpl) getFileContent(token string, contentURL string) (s
 		}
 	}
 
-	return contents, err
+	return contents, statusCode, err
 }
 
 // Create a 'check run' on github.
@@ -231,7 +281,7 @@ func (this *GitHubClientImpl) CreateCheckRun(tokenSupplier TokenSupplier, webhoo
 
 			// Post a status back to github.
 			var req *http.Request
-			checkRunsUrl := webhook.Repository.RepositoryURL + "/check-runs"
+			checkRunsUrl := fmt.Sprintf("%s/repos/%s/check-runs", this.apiBaseUrl, webhook.Repository.FullName)
 			req, err = http.NewRequest("POST", checkRunsUrl, bytes.NewReader(checkRunBytes))
 			if err == nil {
 
@@ -273,12 +323,23 @@ func (this *GitHubClientImpl) CreateCheckRun(tokenSupplier TokenSupplier, webhoo
 
 }
 
+// Github rejects a check run update which has more annotations than this.
+const MAX_ANNOTATIONS_PER_CHECK_RUN_UPDATE = 50
+
+// The most skipped files we list in the summary of a check run.
+const MAX_SKIPPED_FILES_IN_SUMMARY = 50
+
 // Update the status of a previously-created 'check run' which exists at the end of a URL in github.
+//
+// Github only accepts a limited number of annotations in each update, so if there are lots of
+// check errors, the annotations are sent in batches. All but the last batch leave the check run
+// in progress, and only the last batch completes it with a conclusion.
 func (this *GitHubClientImpl) UpdateCheckRun(
 	tokenSupplier TokenSupplier,
 	webhook *Webhook,
 	checkRunURL string,
 	checkErrors []checkTypes.CheckError,
+	skippedFiles []checkTypes.SkippedFile,
 	fatalError string,
 ) error {
 
@@ -288,80 +349,372 @@ func (this *GitHubClientImpl) UpdateCheckRun(
 	token, err = tokenSupplier.GetToken(webhook.Installation.Id)
 	if err == nil {
 
-		checkRun := CheckRun{
-			Name:   "copyright",
-			Status: "completed",
-			Output: CheckRunOutput{
-				Title:   "Galasa copyright check",
-				Summary: "Checks for updated copyright years and licence text",
-			},
-		}
-
 		conclusion := "success"
+		summary := "Checks for updated copyright years and licence text"
+		annotations := make([]CheckRunAnnotation, 0)
 
 		if fatalError != "" {
 			conclusion = "failure"
-			checkRun.Output.Summary = fatalError
+			summary = fatalError
 		} else if len(checkErrors) > 0 {
-			conclusion = "failure"
-			annotations := make([]CheckRunAnnotation, 0)
+			thirdPartyCount := countThirdPartyCheckErrors(checkErrors)
+			problemCount := len(checkErrors) - thirdPartyCount
+
+			if problemCount > 0 {
+				conclusion = "failure"
+				summary = fmt.Sprintf("%s\n\nFound %d problem(s).", summary, problemCount)
+			} else {
+				// Third-party code isn't wrong, but someone has to approve it before it is merged.
+				conclusion = "action_required"
+			}
+
+			if thirdPartyCount > 0 {
+				summary = fmt.Sprintf("%s\n\nFound %d file(s) of third-party code which need legal review.", summary, thirdPartyCount)
+			}
 
 			for _, checkError := range checkErrors {
-				annotation := CheckRunAnnotation{
-					Path:      checkError.Path,
-					Message:   checkError.Message,
-					Level:     "failure",
-					StartLine: 1,
-					EndLine:   1,
+				annotations = append(annotations, newCheckRunAnnotation(checkError))
+			}
+		}
+
+		if fatalError == "" && len(skippedFiles) > 0 {
+			summary = summary + "\n\n" + buildSkippedFilesSummary(skippedFiles)
+		}
+
+		batches := splitAnnotationsIntoBatches(annotations, MAX_ANNOTATIONS_PER_CHECK_RUN_UPDATE)
+
+		for batchIndex, batch := range batches {
+			isLastBatch := (batchIndex == len(batches)-1)
+
+			checkRun := CheckRun{
+				Name:   "copyright",
+				Status: "in_progress",
+				Output: CheckRunOutput{
+					Title:   "Galasa copyright check",
+					Summary: summary,
+				},
+			}
+
+			if len(batch) > 0 {
+				annotationsInBatch := batch
+				checkRun.Output.Annotations = &annotationsInBatch
+			}
+
+			if isLastBatch {
+				checkRun.Status = "completed"
+				checkRun.Conclusion = &conclusion
+			}
+
+			err = this.patchCheckRun(token, checkRunURL, &checkRun)
+			if err != nil {
+				if !isLastBatch {
+					// Don't leave the check run stuck in progress. Complete it without the rest of the annotations.
+					this.patchCheckRun(token, checkRunURL, &CheckRun{
+						Name:       "copyright",
+						Status:     "completed",
+						Conclusion: &conclusion,
+						Output: CheckRunOutput{
+							Title:   "Galasa copyright check",
+							Summary: fmt.Sprintf("%s\n\nNot all problems could be annotated. %v", summary, err),
+						},
+					})
 				}
-				annotations = append(annotations, annotation)
+				break
 			}
+		}
+	}
 
-			checkRun.Output.Annotations = &annotations
+	if err != nil {
+		log.Printf("Fatal error - %v\n", err)
+	}
+
+	return err
+}
+
+func countThirdPartyCheckErrors(checkErrors []checkTypes.CheckError) int {
+	count := 0
+	for _, checkError := range checkErrors {
+		if checkError.IsThirdParty {
+			count++
 		}
-		checkRun.Conclusion = &conclusion
+	}
+	return count
+}
 
-		var checkRunBytes []byte
-		checkRunBytes, err = json.Marshal(&checkRun)
+// Lists the files which were not checked, and why, so nobody thinks they passed.
+// Files with a copyright-check directive are listed apart, so reviewers can see which files opted out.
+func buildSkippedFilesSummary(skippedFiles []checkTypes.SkippedFile) string {
+	suppressedFiles := make([]checkTypes.SkippedFile, 0)
+	otherSkippedFiles := make([]checkTypes.SkippedFile, 0)
+	for _, skippedFile := range skippedFiles {
+		if skippedFile.IsSuppressed {
+			suppressedFiles = append(suppressedFiles, skippedFile)
+		} else {
+			otherSkippedFiles = append(otherSkippedFiles, skippedFile)
+		}
+	}
+
+	sections := make([]string, 0)
+	if len(suppressedFiles) > 0 {
+		sections = append(sections, buildFileListSummary(fmt.Sprintf("Suppressed %d file(s) with copyright-check directives:", len(suppressedFiles)), suppressedFiles))
+	}
+	if len(otherSkippedFiles) > 0 {
+		sections = append(sections, buildFileListSummary(fmt.Sprintf("Skipped %d file(s):", len(otherSkippedFiles)), otherSkippedFiles))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// Only the first few files are listed, as the summary of a check run can't be very long.
+func buildFileListSummary(title string, skippedFiles []checkTypes.SkippedFile) string {
+	var buffer strings.Builder
+	buffer.WriteString(title)
+
+	for index, skippedFile := range skippedFiles {
+		if index >= MAX_SKIPPED_FILES_IN_SUMMARY {
+			buffer.WriteString(fmt.Sprintf("\n- and %d more", len(skippedFiles)-index))
+			break
+		}
+		buffer.WriteString(fmt.Sprintf("\n- `%s` - %s", skippedFile.Path, skippedFile.Reason))
+	}
+
+	return buffer.String()
+}
+
+// Splits the annotations into batches of no more than batchSize.
+// There is always at least one batch, even if there are no annotations.
+func splitAnnotationsIntoBatches(annotations []CheckRunAnnotation, batchSize int) [][]CheckRunAnnotation {
+	batches := make([][]CheckRunAnnotation, 0)
+	for len(annotations) > batchSize {
+		batches = append(batches, annotations[:batchSize])
+		annotations = annotations[batchSize:]
+	}
+	batches = append(batches, annotations)
+	return batches
+}
+
+func (this *GitHubClientImpl) patchCheckRun(token string, checkRunURL string, checkRun *CheckRun) error {
+	var err error = nil
+
+	var checkRunBytes []byte
+	checkRunBytes, err = json.Marshal(checkRun)
+	if err == nil {
+
+		var req *http.Request
+		req, err = http.NewRequest("PATCH", checkRunURL, bytes.NewReader(checkRunBytes))
 		if err == nil {
 
-			var req *http.Request
-			req, err = http.NewRequest("PATCH", checkRunURL, bytes.NewReader(checkRunBytes))
+			req.Header.Add("Authorization", "Bearer "+token)
+			req.Header.Add("Accept", "application/vnd.github.v3+json")
+			req.Header.Add("Content-Type", "application/vnd.github.v3+json")
+
+			var resp *http.Response
+			log.Printf("Sending HTTP PATCH to %s", checkRunURL)
+			resp, err = this.httpClient.Do(req)
 			if err == nil {
 
-				req.Header.Add("Authorization", "Bearer "+token)
-				req.Header.Add("Accept", "application/vnd.github.v3+json")
-				req.Header.Add("Content-Type", "application/vnd.github.v3+json")
+				defer resp.Body.Close()
 
-				var resp *http.Response
-				log.Printf("Sending HTTP PATCH to %s", checkRunURL)
-				resp, err = this.httpClient.Do(req)
+				var bodyBytes []byte
+				bodyBytes, err = io.ReadAll(resp.Body)
 				if err == nil {
 
-					defer resp.Body.Close()
+					data := string(bodyBytes)
 
-					var bodyBytes []byte
-					bodyBytes, err = io.ReadAll(resp.Body)
-					if err == nil {
+					if resp.StatusCode != 200 {
+						log.Printf("Fatal error - %v\n", data)
+						err = errors.New(fmt.Sprintf("Non-200 status returned from github. %d", resp.StatusCode))
+					} else {
+						this.LogHttpPayload(bodyBytes)
+					}
+				}
+			}
+		}
+	}
 
-						data := string(bodyBytes)
+	return err
+}
 
-						if resp.StatusCode != 200 {
-							log.Printf("Fatal error - %v\n", data)
-							err = errors.New(fmt.Sprintf("Non-200 status returned from github. %d", resp.StatusCode))
-						} else {
-							this.LogHttpPayload(bodyBytes)
-						}
-					}
+// Submits a review of a pull request, which can include comments on lines of the files changed.
+func (this *GitHubClientImpl) CreatePullRequestReview(token string, repository *WebhookRepository, pullRequestNumber int, review *PullRequestReview) error {
+	reviewsUrl := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", this.apiBaseUrl, repository.FullName, pullRequestNumber)
+	return this.sendJsonRequest("POST", reviewsUrl, token, review, http.StatusOK, nil)
+}
+
+// Gets all the reviews which have been made of a pull request, oldest first.
+func (this *GitHubClientImpl) GetPullRequestReviews(token string, repository *WebhookRepository, pullRequestNumber int) ([]PullRequestReview, error) {
+	var err error = nil
+	reviews := make([]PullRequestReview, 0)
+
+	// Keep asking for pages of reviews until we get an empty page or an error.
+	for pageNumber := 1; err == nil; pageNumber++ {
+		pageOfReviews := make([]PullRequestReview, 0)
+		reviewsUrl := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?per_page=100&page=%d", this.apiBaseUrl, repository.FullName, pullRequestNumber, pageNumber)
+		err = this.sendJsonRequest("GET", reviewsUrl, token, nil, http.StatusOK, &pageOfReviews)
+		if len(pageOfReviews) < 1 {
+			break
+		}
+		reviews = append(reviews, pageOfReviews...)
+	}
+
+	return reviews, err
+}
+
+// Adds a commit to the end of a branch, which changes the content of some files.
+// The index of contentsByPath is the path of the file in the repository, the value is its new content.
+// Returns the sha of the new commit.
+//
+// The branch is only moved on if it still points at parentSha, so nobody else's changes are lost.
+func (this *GitHubClientImpl) CommitFiles(
+	token string,
+	repository *WebhookRepository,
+	branch string,
+	parentSha string,
+	message string,
+	contentsByPath map[string]string,
+) (string, error) {
+	var err error = nil
+	commitSha := ""
+	repoUrl := fmt.Sprintf("%s/repos/%s", this.apiBaseUrl, repository.FullName)
+
+	var parentCommit GitCommit
+	err = this.sendJsonRequest("GET", repoUrl+"/git/commits/"+parentSha, token, nil, http.StatusOK, &parentCommit)
+	if err == nil {
+
+		// We need to know the modes of the files we are changing, so executable scripts stay executable.
+		var parentTree GitTree
+		err = this.sendJsonRequest("GET", repoUrl+"/git/trees/"+parentCommit.Tree.Sha+"?recursive=1", token, nil, http.StatusOK, &parentTree)
+		if err == nil {
+
+			modesByPath := make(map[string]string)
+			for _, entry := range parentTree.Tree {
+				modesByPath[entry.Path] = entry.Mode
+			}
+
+			paths := make([]string, 0, len(contentsByPath))
+			for path := range contentsByPath {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+
+			newTree := GitTree{BaseTree: parentCommit.Tree.Sha, Tree: make([]GitTreeEntry, 0, len(paths))}
+			for _, path := range paths {
+				mode, isFound := modesByPath[path]
+				if !isFound {
+					mode = "100644"
 				}
+				newTree.Tree = append(newTree.Tree, GitTreeEntry{
+					Path:    path,
+					Mode:    mode,
+					Type:    "blob",
+					Content: contentsByPath[path],
+				})
 			}
 
+			var createdTree GitTree
+			err = this.sendJsonRequest("POST", repoUrl+"/git/trees", token, &newTree, http.StatusCreated, &createdTree)
+			if err == nil {
+
+				newCommit := GitCommitRequest{
+					Message: message,
+					Tree:    createdTree.Sha,
+					Parents: []string{parentSha},
+				}
+
+				var createdCommit GitCommit
+				err = this.sendJsonRequest("POST", repoUrl+"/git/commits", token, &newCommit, http.StatusCreated, &createdCommit)
+				if err == nil {
+
+					refUpdate := GitRefUpdate{Sha: createdCommit.Sha, Force: false}
+					err = this.sendJsonRequest("PATCH", repoUrl+"/git/refs/heads/"+branch, token, &refUpdate, http.StatusOK, nil)
+					if err == nil {
+						commitSha = createdCommit.Sha
+					}
+				}
+			}
 		}
 	}
 
-	if err != nil {
-		log.Printf("Fatal error - %v\n", err)
+	return commitSha, err
+}
+
+// Sends a request to github. If there is a requestBody it is sent as json.
+// If there is a responseBody, the json returned by github is read into it.
+func (this *GitHubClientImpl) sendJsonRequest(method string, url string, token string, requestBody interface{}, expectedStatusCode int, responseBody interface{}) error {
+	var err error = nil
+
+	var bodyReader io.Reader = nil
+	if requestBody != nil {
+		var requestBytes []byte
+		requestBytes, err = json.Marshal(requestBody)
+		bodyReader = bytes.NewReader(requestBytes)
+	}
+
+	if err == nil {
+		var req *http.Request
+		req, err = http.NewRequest(method, url, bodyReader)
+		if err == nil {
+
+			req.Header.Add("Authorization", "Bearer "+token)
+			req.Header.Add("Accept", "application/vnd.github.v3+json")
+			if requestBody != nil {
+				req.Header.Add("Content-Type", "application/vnd.github.v3+json")
+			}
+
+			log.Printf("Sending HTTP %s to %s", method, url)
+
+			var resp *http.Response
+			resp, err = this.httpClient.Do(req)
+			if err == nil {
+
+				defer resp.Body.Close()
+
+				var bodyBytes []byte
+				bodyBytes, err = io.ReadAll(resp.Body)
+				if err == nil {
+
+					this.LogHttpPayload(bodyBytes)
+
+					if resp.StatusCode != expectedStatusCode {
+						err = errors.New(fmt.Sprintf("Got status code %d from HTTP %s to %s. Expected %d.", resp.StatusCode, method, url, expectedStatusCode))
+					} else if responseBody != nil {
+						err = json.Unmarshal(bodyBytes, responseBody)
+					}
+				}
+			}
+		}
 	}
 
 	return err
 }
+
+// Turns a check error into an annotation which points at the lines of the file which have the problem.
+func newCheckRunAnnotation(checkError checkTypes.CheckError) CheckRunAnnotation {
+	annotation := CheckRunAnnotation{
+		Path:      checkError.Path,
+		Message:   checkError.Message,
+		Level:     "failure",
+		StartLine: checkError.StartLine,
+		EndLine:   checkError.EndLine,
+	}
+
+	if checkError.IsThirdParty {
+		annotation.Level = "warning"
+	}
+
+	if annotation.StartLine < 1 {
+		// We don't know where the problem is, so point at the top of the file.
+		annotation.StartLine = 1
+		annotation.EndLine = 1
+	} else if annotation.EndLine < annotation.StartLine {
+		annotation.EndLine = annotation.StartLine
+	}
+
+	// Github only allows columns on annotations which are within a single line.
+	if annotation.StartLine == annotation.EndLine && checkError.StartColumn > 0 && checkError.EndColumn >= checkError.StartColumn {
+		annotation.StartColumn = checkError.StartColumn
+		annotation.EndColumn = checkError.EndColumn
+	}
+
+	return annotation
+}
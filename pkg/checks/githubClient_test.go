@@ -0,0 +1,405 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNewTokenUsesConfiguredApiBaseUrl(t *testing.T) {
+	// Given
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token":"my-token","expires_at":"2030-01-01T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL+"/api/v3/", server.URL+"/api/uploads", false)
+
+	// When..
+	token, err := client.GetNewToken(1234, "jwt")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "my-token", token.Token)
+	assert.Equal(t, "/api/v3/app/installations/1234/access_tokens", requestedPath)
+}
+
+func TestCreateCheckRunUsesConfiguredApiBaseUrl(t *testing.T) {
+	// Given
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name":"copyright","url":"https://my.ghes/check-runs/1"}`))
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL+"/api/v3", server.URL+"/api/uploads", false)
+	tokenSupplier := &TokenSupplierMock{tokenToReturn: "installation-token"}
+	webhook := &Webhook{Repository: WebhookRepository{FullName: "galasa-dev/framework"}}
+
+	// When..
+	checkRunUrl, err := client.CreateCheckRun(tokenSupplier, webhook, "abc123")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "https://my.ghes/check-runs/1", checkRunUrl)
+	assert.Equal(t, "/api/v3/repos/galasa-dev/framework/check-runs", requestedPath)
+}
+
+func TestGetRepositoryFileContentReturnsContentAtRef(t *testing.T) {
+	// Given
+	var requestedUri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedUri = r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("holder: Copyright Acme Corp.\n"))
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	repository := &WebhookRepository{FullName: "galasa-dev/framework"}
+
+	// When..
+	content, isFound, err := client.GetRepositoryFileContent("token", repository, ".github/copyright.yaml", "abc123")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.True(t, isFound)
+	assert.Equal(t, "holder: Copyright Acme Corp.\n", content)
+	assert.Equal(t, "/repos/galasa-dev/framework/contents/.github/copyright.yaml?ref=abc123", requestedUri)
+}
+
+func TestGetRepositoryFileContentEscapesEachSegmentOfThePath(t *testing.T) {
+	// Given
+	var requestedUri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedUri = r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	repository := &WebhookRepository{FullName: "galasa-dev/framework"}
+
+	// When..
+	_, _, err := client.GetRepositoryFileContent("token", repository, "docs/my notes/#1 100%?.md.license", "abc123")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "/repos/galasa-dev/framework/contents/docs/my%20notes/%231%20100%25%3F.md.license?ref=abc123", requestedUri)
+}
+
+func TestGetRepositoryFileContentOfMissingFileIsNotFoundButNotAnError(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	repository := &WebhookRepository{FullName: "galasa-dev/framework"}
+
+	// When..
+	_, isFound, err := client.GetRepositoryFileContent("token", repository, ".github/copyright.yaml", "abc123")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.False(t, isFound)
+}
+
+func TestAnnotationForCheckErrorWithoutLinesPointsAtFirstLine(t *testing.T) {
+	annotation := newCheckRunAnnotation(*checkTypes.NewCheckError("test.java", "message", 0))
+	assert.Equal(t, 1, annotation.StartLine)
+	assert.Equal(t, 1, annotation.EndLine)
+	assert.Equal(t, 0, annotation.StartColumn)
+	assert.Equal(t, 0, annotation.EndColumn)
+}
+
+func TestAnnotationForCheckErrorOverSeveralLinesHasNoColumns(t *testing.T) {
+	checkError := checkTypes.NewCheckErrorForRange("test.java", "message", "one\ntwo\nthree\n", 4, 13)
+	annotation := newCheckRunAnnotation(*checkError)
+	assert.Equal(t, 2, annotation.StartLine)
+	assert.Equal(t, 3, annotation.EndLine)
+	assert.Equal(t, 0, annotation.StartColumn)
+	assert.Equal(t, 0, annotation.EndColumn)
+}
+
+func TestAnnotationForCheckErrorWithinOneLineHasColumns(t *testing.T) {
+	checkError := checkTypes.NewCheckErrorForRange("test.java", "message", "one\ntwo three\n", 8, 13)
+	annotation := newCheckRunAnnotation(*checkError)
+	assert.Equal(t, 2, annotation.StartLine)
+	assert.Equal(t, 2, annotation.EndLine)
+	assert.Equal(t, 5, annotation.StartColumn)
+	assert.Equal(t, 9, annotation.EndColumn)
+}
+
+func TestUpdateCheckRunSendsAnnotationsInBatchesOfFifty(t *testing.T) {
+	// Given
+	updates := make([]CheckRun, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var checkRun CheckRun
+		json.NewDecoder(r.Body).Decode(&checkRun)
+		updates = append(updates, checkRun)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	tokenSupplier := &TokenSupplierMock{tokenToReturn: "installation-token"}
+
+	checkErrors := make([]checkTypes.CheckError, 0)
+	for i := 0; i < 120; i++ {
+		checkErrors = append(checkErrors, *checkTypes.NewCheckError(fmt.Sprintf("file%d.java", i), "message", 0))
+	}
+
+	// When..
+	err := client.UpdateCheckRun(tokenSupplier, &Webhook{}, server.URL+"/check-runs/1", checkErrors, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(updates))
+
+	assert.Equal(t, "in_progress", updates[0].Status)
+	assert.Nil(t, updates[0].Conclusion)
+	assert.Equal(t, 50, len(*updates[0].Output.Annotations))
+	assert.Equal(t, "file0.java", (*updates[0].Output.Annotations)[0].Path)
+
+	assert.Equal(t, "in_progress", updates[1].Status)
+	assert.Nil(t, updates[1].Conclusion)
+	assert.Equal(t, 50, len(*updates[1].Output.Annotations))
+	assert.Equal(t, "file50.java", (*updates[1].Output.Annotations)[0].Path)
+
+	assert.Equal(t, "completed", updates[2].Status)
+	assert.Equal(t, "failure", *updates[2].Conclusion)
+	assert.Equal(t, 20, len(*updates[2].Output.Annotations))
+	assert.Equal(t, "file100.java", (*updates[2].Output.Annotations)[0].Path)
+
+	for _, update := range updates {
+		assert.Contains(t, update.Output.Summary, "Found 120 problem(s).")
+	}
+}
+
+func TestUpdateCheckRunWithNoErrorsSendsOneSuccessfulUpdate(t *testing.T) {
+	// Given
+	updates := make([]CheckRun, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var checkRun CheckRun
+		json.NewDecoder(r.Body).Decode(&checkRun)
+		updates = append(updates, checkRun)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	tokenSupplier := &TokenSupplierMock{tokenToReturn: "installation-token"}
+
+	// When..
+	err := client.UpdateCheckRun(tokenSupplier, &Webhook{}, server.URL+"/check-runs/1", nil, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(updates))
+	assert.Equal(t, "completed", updates[0].Status)
+	assert.Equal(t, "success", *updates[0].Conclusion)
+	assert.Nil(t, updates[0].Output.Annotations)
+}
+
+func TestUpdateCheckRunListsSkippedFilesInSummary(t *testing.T) {
+	// Given
+	updates := make([]CheckRun, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var checkRun CheckRun
+		json.NewDecoder(r.Body).Decode(&checkRun)
+		updates = append(updates, checkRun)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	tokenSupplier := &TokenSupplierMock{tokenToReturn: "installation-token"}
+
+	skippedFiles := []checkTypes.SkippedFile{
+		*checkTypes.NewSkippedFile("images/logo.png", "binary content"),
+		*checkTypes.NewSuppressedFile("src/Upstream.java", "copyright-check: allow-license Apache-2.0"),
+		*checkTypes.NewSkippedFile("dist/bundle.js", "marked linguist-generated in .gitattributes"),
+	}
+
+	// When..
+	err := client.UpdateCheckRun(tokenSupplier, &Webhook{}, server.URL+"/check-runs/1", nil, skippedFiles, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(updates))
+	assert.Equal(t, "success", *updates[0].Conclusion)
+	assert.Contains(t, updates[0].Output.Summary, "Suppressed 1 file(s) with copyright-check directives:\n- `src/Upstream.java` - copyright-check: allow-license Apache-2.0")
+	assert.Contains(t, updates[0].Output.Summary, "Skipped 2 file(s):\n- `images/logo.png` - binary content\n- `dist/bundle.js` - marked linguist-generated in .gitattributes")
+}
+
+func TestUpdateCheckRunWithOnlyThirdPartyCodeNeedsAction(t *testing.T) {
+	// Given
+	updates := make([]CheckRun, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var checkRun CheckRun
+		json.NewDecoder(r.Body).Decode(&checkRun)
+		updates = append(updates, checkRun)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	tokenSupplier := &TokenSupplierMock{tokenToReturn: "installation-token"}
+
+	thirdPartyCheckError := checkTypes.NewCheckError("src/Copied.java", "Third-party code: needs legal review.", 0)
+	thirdPartyCheckError.IsThirdParty = true
+	checkErrors := []checkTypes.CheckError{*thirdPartyCheckError}
+
+	// When..
+	err := client.UpdateCheckRun(tokenSupplier, &Webhook{}, server.URL+"/check-runs/1", checkErrors, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(updates))
+	assert.Equal(t, "action_required", *updates[0].Conclusion)
+	assert.Equal(t, "warning", (*updates[0].Output.Annotations)[0].Level)
+	assert.Contains(t, updates[0].Output.Summary, "Found 1 file(s) of third-party code which need legal review.")
+	assert.NotContains(t, updates[0].Output.Summary, "problem(s)")
+}
+
+func TestUpdateCheckRunWithThirdPartyCodeAndOtherProblemsFails(t *testing.T) {
+	// Given
+	updates := make([]CheckRun, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var checkRun CheckRun
+		json.NewDecoder(r.Body).Decode(&checkRun)
+		updates = append(updates, checkRun)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	tokenSupplier := &TokenSupplierMock{tokenToReturn: "installation-token"}
+
+	thirdPartyCheckError := checkTypes.NewCheckError("src/Copied.java", "Third-party code: needs legal review.", 0)
+	thirdPartyCheckError.IsThirdParty = true
+	checkErrors := []checkTypes.CheckError{*thirdPartyCheckError, *checkTypes.NewCheckError("src/Mine.java", "message", 0)}
+
+	// When..
+	err := client.UpdateCheckRun(tokenSupplier, &Webhook{}, server.URL+"/check-runs/1", checkErrors, nil, "")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "failure", *updates[0].Conclusion)
+	assert.Contains(t, updates[0].Output.Summary, "Found 1 problem(s).")
+	assert.Contains(t, updates[0].Output.Summary, "Found 1 file(s) of third-party code which need legal review.")
+	assert.Equal(t, "failure", (*updates[0].Output.Annotations)[1].Level)
+}
+
+func TestUpdateCheckRunCompletesWithoutRemainingBatchesAfterAFailure(t *testing.T) {
+	// Given
+	updates := make([]CheckRun, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var checkRun CheckRun
+		json.NewDecoder(r.Body).Decode(&checkRun)
+		updates = append(updates, checkRun)
+		if checkRun.Output.Annotations != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	tokenSupplier := &TokenSupplierMock{tokenToReturn: "installation-token"}
+
+	checkErrors := make([]checkTypes.CheckError, 0)
+	for i := 0; i < 60; i++ {
+		checkErrors = append(checkErrors, *checkTypes.NewCheckError("file.java", "message", 0))
+	}
+
+	// When..
+	err := client.UpdateCheckRun(tokenSupplier, &Webhook{}, server.URL+"/check-runs/1", checkErrors, nil, "")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(updates))
+	assert.Equal(t, "completed", updates[1].Status)
+	assert.Equal(t, "failure", *updates[1].Conclusion)
+	assert.Contains(t, updates[1].Output.Summary, "Not all problems could be annotated.")
+}
+
+func TestCommitFilesCreatesCommitOnBranchKeepingFileModes(t *testing.T) {
+	// Given
+	var createdTree GitTree
+	var createdCommit GitCommitRequest
+	var refUpdate GitRefUpdate
+	var refPath string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/galasa-dev/framework/git/commits/parent-sha":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sha":"parent-sha","tree":{"sha":"parent-tree-sha"}}`))
+		case r.Method == "GET" && r.URL.Path == "/repos/galasa-dev/framework/git/trees/parent-tree-sha":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"sha":"parent-tree-sha","tree":[{"path":"build.sh","mode":"100755","type":"blob"}]}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/galasa-dev/framework/git/trees":
+			json.NewDecoder(r.Body).Decode(&createdTree)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"sha":"new-tree-sha","tree":[]}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/galasa-dev/framework/git/commits":
+			json.NewDecoder(r.Body).Decode(&createdCommit)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"sha":"new-commit-sha","tree":{"sha":"new-tree-sha"}}`))
+		case r.Method == "PATCH":
+			refPath = r.URL.Path
+			json.NewDecoder(r.Body).Decode(&refUpdate)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient(server.URL, server.URL, false)
+	repository := &WebhookRepository{FullName: "galasa-dev/framework"}
+	contentsByPath := map[string]string{
+		"src/MyClass.java": "fixed java",
+		"build.sh":         "fixed script",
+	}
+
+	// When..
+	commitSha, err := client.CommitFiles("token", repository, "my-branch", "parent-sha", "Fix copyright headers", contentsByPath)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "new-commit-sha", commitSha)
+
+	assert.Equal(t, "parent-tree-sha", createdTree.BaseTree)
+	assert.Equal(t, 2, len(createdTree.Tree))
+	assert.Equal(t, GitTreeEntry{Path: "build.sh", Mode: "100755", Type: "blob", Content: "fixed script"}, createdTree.Tree[0])
+	assert.Equal(t, GitTreeEntry{Path: "src/MyClass.java", Mode: "100644", Type: "blob", Content: "fixed java"}, createdTree.Tree[1])
+
+	assert.Equal(t, "new-tree-sha", createdCommit.Tree)
+	assert.Equal(t, []string{"parent-sha"}, createdCommit.Parents)
+	assert.Equal(t, "Fix copyright headers", createdCommit.Message)
+
+	assert.Equal(t, "/repos/galasa-dev/framework/git/refs/heads/my-branch", refPath)
+	assert.Equal(t, "new-commit-sha", refUpdate.Sha)
+	assert.False(t, refUpdate.Force)
+}
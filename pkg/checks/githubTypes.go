@@ -38,6 +38,7 @@ type WebhookPullRequest struct {
 
 type WebhookPullRequestHead struct {
 	Sha  string            `json:"sha"`
+	Ref  string            `json:"ref"`
 	Repo WebhookRepository `json:"repo"`
 }
 
@@ -48,6 +49,7 @@ type WebhookInstallation struct {
 
 type WebhookRepository struct {
 	Id            int    `json:"id"`
+	FullName      string `json:"full_name"`
 	RepositoryURL string `json:"url"`
 	CompareURL    string `json:"compare_url"`
 	CommitsURL    string `json:"commits_url"`
@@ -63,6 +65,7 @@ type File struct {
 	Filename    string `json:"filename"`
 	Status      string `json:"status"`
 	ContentsURL string `json:"contents_url"`
+	Patch       string `json:"patch,omitempty"`
 }
 
 type CheckRun struct {
@@ -81,13 +84,65 @@ type CheckRunOutput struct {
 }
 
 type CheckRunAnnotation struct {
-	Path      string `json:"path"`
-	StartLine int    `json:"start_line"`
-	EndLine   int    `json:"end_line"`
-	Level     string `json:"annotation_level"`
-	Message   string `json:"message"`
+	Path        string `json:"path"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	StartColumn int    `json:"start_column,omitempty"`
+	EndColumn   int    `json:"end_column,omitempty"`
+	Level       string `json:"annotation_level"`
+	Message     string `json:"message"`
 }
 
 type Files struct {
 	Files *[]File `json:"files"`
 }
+
+type GitObjectRef struct {
+	Sha string `json:"sha"`
+}
+
+type GitCommit struct {
+	Sha  string       `json:"sha"`
+	Tree GitObjectRef `json:"tree"`
+}
+
+type GitCommitRequest struct {
+	Message string   `json:"message"`
+	Tree    string   `json:"tree"`
+	Parents []string `json:"parents"`
+}
+
+type GitTree struct {
+	Sha       string         `json:"sha,omitempty"`
+	BaseTree  string         `json:"base_tree,omitempty"`
+	Tree      []GitTreeEntry `json:"tree"`
+	Truncated bool           `json:"truncated,omitempty"`
+}
+
+type GitTreeEntry struct {
+	Path    string `json:"path"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Content string `json:"content,omitempty"`
+}
+
+type GitRefUpdate struct {
+	Sha   string `json:"sha"`
+	Force bool   `json:"force"`
+}
+
+type PullRequestReview struct {
+	CommitId string                     `json:"commit_id"`
+	Body     string                     `json:"body"`
+	Event    string                     `json:"event"`
+	Comments []PullRequestReviewComment `json:"comments"`
+}
+
+type PullRequestReviewComment struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Side      string `json:"side"`
+	StartLine int    `json:"start_line,omitempty"`
+	StartSide string `json:"start_side,omitempty"`
+	Body      string `json:"body"`
+}
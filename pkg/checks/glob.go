@@ -0,0 +1,216 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A file path pattern. Paths are always relative to the root of the repository, using '/' separators.
+//
+// By default, as in the policy file:
+// - '*' matches any characters except a '/'
+// - '?' matches any single character except a '/'
+// - '**' matches any number of whole folders, including none. eg: "docs/**/*.md"
+// - A pattern with no '/' in it, other than at the end, matches in any folder. eg: "*.java"
+// - A pattern ending in '/' matches everything inside that folder. eg: "vendor/"
+//
+// The patterns in other files work a little differently, which the GlobOptions say.
+type Glob struct {
+	pattern string
+	regex   *regexp.Regexp
+
+	// The pattern started with '!', so it brings back files which an earlier pattern matched.
+	isNegated bool
+
+	// The pattern ended with '/', so it only matches folders.
+	isDirectoryOnly bool
+}
+
+// How the characters in a pattern are treated.
+type GlobOptions struct {
+	// A pattern with no '/' in it, other than at the end, matches in any folder. Otherwise patterns are relative to the root.
+	IsNameMatchedInAnyFolder bool
+
+	// '*' and '?' match '/' too.
+	IsWildcardMatchingFolders bool
+
+	// '**' matches any characters including '/' wherever it is, not just as whole folders.
+	IsDoubleStarMatchingAnywhere bool
+
+	// '?' matches any single character. Otherwise it only matches itself.
+	IsQuestionMarkWildcard bool
+
+	// [a-z] and [!a-z] match one character in or out of a range.
+	IsCharacterClassAllowed bool
+
+	// '\' makes the next character match itself. eg: "\*" or "\!important"
+	IsEscapeAllowed bool
+
+	// A pattern starting with '!' is negated.
+	IsNegationAllowed bool
+
+	// A pattern ending in '/' only matches folders. Otherwise, if names are matched in any folder, it matches everything inside the folder.
+	IsTrailingSlashDirectoryOnly bool
+}
+
+// The patterns in the policy file.
+var policyGlobOptions = GlobOptions{
+	IsNameMatchedInAnyFolder: true,
+	IsQuestionMarkWildcard:   true,
+}
+
+// The patterns in a .gitignore file, and so in a .copyrightignore file. See https://git-scm.com/docs/gitignore
+var gitIgnoreGlobOptions = GlobOptions{
+	IsNameMatchedInAnyFolder:     true,
+	IsQuestionMarkWildcard:       true,
+	IsCharacterClassAllowed:      true,
+	IsEscapeAllowed:              true,
+	IsNegationAllowed:            true,
+	IsTrailingSlashDirectoryOnly: true,
+}
+
+// The patterns in a .gitattributes file, which are the same as in a .gitignore file, except they can't be negated.
+// See https://git-scm.com/docs/gitattributes
+var gitAttributesGlobOptions = GlobOptions{
+	IsNameMatchedInAnyFolder:     true,
+	IsQuestionMarkWildcard:       true,
+	IsCharacterClassAllowed:      true,
+	IsEscapeAllowed:              true,
+	IsTrailingSlashDirectoryOnly: true,
+}
+
+// The paths in a REUSE.toml file, where '*' matches any characters except '/', and '**' matches any characters including '/'.
+// A '*' can be matched literally by escaping it as '\*'.
+var reuseTomlGlobOptions = GlobOptions{
+	IsDoubleStarMatchingAnywhere: true,
+	IsEscapeAllowed:              true,
+}
+
+// The Files patterns in a .reuse/dep5 file, where '*' matches any characters including '/', and '?' matches any single character.
+var dep5GlobOptions = GlobOptions{
+	IsWildcardMatchingFolders: true,
+	IsQuestionMarkWildcard:    true,
+}
+
+func NewGlob(pattern string) (*Glob, error) {
+	return NewGlobWithOptions(pattern, policyGlobOptions)
+}
+
+func NewGlobWithOptions(pattern string, options GlobOptions) (*Glob, error) {
+	var err error = nil
+	this := new(Glob)
+	this.pattern = pattern
+
+	if options.IsNegationAllowed && strings.HasPrefix(pattern, "!") {
+		this.isNegated = true
+		pattern = pattern[1:]
+	}
+
+	if options.IsTrailingSlashDirectoryOnly && strings.HasSuffix(pattern, "/") {
+		this.isDirectoryOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	this.regex, err = regexp.Compile("^" + globToRegexText(pattern, options) + "$")
+	return this, err
+}
+
+// Does the file match the pattern ?
+func (this *Glob) Matches(path string) bool {
+	return this.MatchesPath(path, false)
+}
+
+// Does the file or folder match the pattern ? Patterns which only match folders never match files.
+func (this *Glob) MatchesPath(path string, isDirectory bool) bool {
+	return (isDirectory || !this.isDirectoryOnly) && this.regex.MatchString(path)
+}
+
+// Does the pattern bring back files which an earlier pattern matched ?
+func (this *Glob) IsNegated() bool {
+	return this.isNegated
+}
+
+func (this *Glob) String() string {
+	return this.pattern
+}
+
+func globToRegexText(pattern string, options GlobOptions) string {
+	var buffer strings.Builder
+
+	if options.IsNameMatchedInAnyFolder {
+		if !options.IsTrailingSlashDirectoryOnly && strings.HasSuffix(pattern, "/") {
+			pattern = pattern + "**"
+		}
+
+		folderPattern := pattern
+		if !options.IsTrailingSlashDirectoryOnly {
+			// Everything in a folder, which can be any folder of that name. eg: "vendor/**"
+			folderPattern = strings.TrimSuffix(pattern, "/**")
+		}
+
+		if strings.Contains(folderPattern, "/") {
+			pattern = strings.TrimPrefix(pattern, "/")
+		} else {
+			// No folder was mentioned, so it can match in any folder.
+			buffer.WriteString("(.*/)?")
+		}
+	}
+
+	for index := 0; index < len(pattern); index++ {
+		c := pattern[index]
+		rest := pattern[index:]
+		isFolderStart := (index == 0 || pattern[index-1] == '/')
+
+		switch {
+		case c == '\\' && options.IsEscapeAllowed && index+1 < len(pattern):
+			index++
+			buffer.WriteString(regexp.QuoteMeta(string(pattern[index])))
+		case strings.HasPrefix(rest, "**") && options.IsDoubleStarMatchingAnywhere:
+			buffer.WriteString(".*")
+			index += 1
+		case isFolderStart && strings.HasPrefix(rest, "**/"):
+			// Any number of folders, including none.
+			buffer.WriteString("(.*/)?")
+			index += 2
+		case isFolderStart && rest == "**":
+			buffer.WriteString(".*")
+			index += 1
+		case c == '*' && options.IsWildcardMatchingFolders:
+			buffer.WriteString(".*")
+		case c == '*':
+			buffer.WriteString("[^/]*")
+		case c == '?' && options.IsQuestionMarkWildcard && options.IsWildcardMatchingFolders:
+			buffer.WriteString(".")
+		case c == '?' && options.IsQuestionMarkWildcard:
+			buffer.WriteString("[^/]")
+		case c == '[' && options.IsCharacterClassAllowed && strings.Contains(rest[1:], "]"):
+			end := strings.Index(rest[1:], "]")
+			class := rest[1 : 1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			buffer.WriteString("[" + strings.ReplaceAll(class, "\\", "\\\\") + "]")
+			index += end + 1
+		default:
+			buffer.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return buffer.String()
+}
+
+// Does the path match any of the globs ?
+func matchesAnyGlob(globs []*Glob, path string) bool {
+	isMatched := false
+	for _, glob := range globs {
+		if glob.Matches(path) {
+			isMatched = true
+			break
+		}
+	}
+	return isMatched
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertGlobMatches(t *testing.T, pattern string, path string, expected bool) {
+	glob, err := NewGlob(pattern)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, glob.Matches(path), "pattern '%s' path '%s'", pattern, path)
+}
+
+func TestGlobWithNoFolderMatchesFileNameInAnyFolder(t *testing.T) {
+	assertGlobMatches(t, "*.java", "MyClass.java", true)
+	assertGlobMatches(t, "*.java", "src/main/java/MyClass.java", true)
+	assertGlobMatches(t, "*.java", "src/main/java/MyClass.go", false)
+}
+
+func TestGlobWithFolderIsAnchoredToRoot(t *testing.T) {
+	assertGlobMatches(t, "src/*.go", "src/main.go", true)
+	assertGlobMatches(t, "src/*.go", "src/pkg/main.go", false)
+	assertGlobMatches(t, "src/*.go", "other/src/main.go", false)
+	assertGlobMatches(t, "/src/*.go", "src/main.go", true)
+}
+
+func TestGlobDoubleStarMatchesAnyNumberOfFolders(t *testing.T) {
+	assertGlobMatches(t, "docs/**/*.md", "docs/index.md", true)
+	assertGlobMatches(t, "docs/**/*.md", "docs/a/b/c/index.md", true)
+	assertGlobMatches(t, "docs/**/*.md", "src/docs/index.md", false)
+	assertGlobMatches(t, "**/generated/**", "generated/a.go", true)
+	assertGlobMatches(t, "**/generated/**", "pkg/generated/a/b.go", true)
+	assertGlobMatches(t, "**", "anything/at/all.txt", true)
+}
+
+func TestGlobEndingInSlashMatchesFolderContents(t *testing.T) {
+	assertGlobMatches(t, "vendor/", "vendor/github.com/x/y.go", true)
+	assertGlobMatches(t, "vendor/", "pkg/vendor/y.go", true)
+	assertGlobMatches(t, "pkg/vendor/", "vendor/y.go", false)
+}
+
+func TestGlobQuestionMarkMatchesOneCharacter(t *testing.T) {
+	assertGlobMatches(t, "file?.txt", "file1.txt", true)
+	assertGlobMatches(t, "file?.txt", "file12.txt", false)
+}
+
+func TestGlobDotsAreLiteral(t *testing.T) {
+	assertGlobMatches(t, "*.go", "main_go", false)
+}
+
+func assertGlobWithOptionsMatches(t *testing.T, options GlobOptions, pattern string, path string, expected bool) {
+	glob, err := NewGlobWithOptions(pattern, options)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, glob.Matches(path), "pattern '%s' path '%s'", pattern, path)
+}
+
+func TestGitIgnoreGlobIsNegatedByExclamationMark(t *testing.T) {
+	glob, err := NewGlobWithOptions("!keep.java", gitIgnoreGlobOptions)
+	assert.Nil(t, err)
+	assert.True(t, glob.IsNegated())
+	assert.True(t, glob.Matches("src/keep.java"))
+	assertGlobWithOptionsMatches(t, gitIgnoreGlobOptions, "\\!keep.java", "!keep.java", true)
+}
+
+func TestGitIgnoreGlobEndingInSlashOnlyMatchesFolders(t *testing.T) {
+	glob, err := NewGlobWithOptions("build/", gitIgnoreGlobOptions)
+	assert.Nil(t, err)
+	assert.True(t, glob.MatchesPath("pkg/build", true))
+	assert.False(t, glob.MatchesPath("pkg/build", false))
+	assert.False(t, glob.MatchesPath("pkg/build/a.go", false))
+}
+
+func TestGitIgnoreGlobMatchesCharacterClasses(t *testing.T) {
+	assertGlobWithOptionsMatches(t, gitIgnoreGlobOptions, "file[0-9].txt", "file1.txt", true)
+	assertGlobWithOptionsMatches(t, gitIgnoreGlobOptions, "file[!0-9].txt", "file1.txt", false)
+	assertGlobWithOptionsMatches(t, gitIgnoreGlobOptions, "file[!0-9].txt", "fileA.txt", true)
+	assertGlobMatches(t, "file[0-9].txt", "file1.txt", false)
+}
+
+func TestGitIgnoreGlobWithFolderInMiddleIsAnchoredToRoot(t *testing.T) {
+	assertGlobWithOptionsMatches(t, gitIgnoreGlobOptions, "src/**", "src/a/b.go", true)
+	assertGlobWithOptionsMatches(t, gitIgnoreGlobOptions, "src/**", "pkg/src/b.go", false)
+}
+
+func TestReuseTomlGlobDoubleStarMatchesAnywhere(t *testing.T) {
+	assertGlobWithOptionsMatches(t, reuseTomlGlobOptions, "images/**.png", "images/a/b.png", true)
+	assertGlobWithOptionsMatches(t, reuseTomlGlobOptions, "images/*.png", "images/a/b.png", false)
+	assertGlobWithOptionsMatches(t, reuseTomlGlobOptions, "*.png", "images/b.png", false)
+	assertGlobWithOptionsMatches(t, reuseTomlGlobOptions, "\\*.png", "*.png", true)
+	assertGlobWithOptionsMatches(t, reuseTomlGlobOptions, "?.png", "a.png", false)
+}
+
+func TestDep5GlobWildcardsMatchFolders(t *testing.T) {
+	assertGlobWithOptionsMatches(t, dep5GlobOptions, "images/*", "images/a/b.png", true)
+	assertGlobWithOptionsMatches(t, dep5GlobOptions, "images/?/b.png", "images/a/b.png", true)
+	assertGlobWithOptionsMatches(t, dep5GlobOptions, "*.png", "images/b.png", true)
+	assertGlobWithOptionsMatches(t, dep5GlobOptions, "images/*", "docs/b.png", false)
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// Checks files on the local file system, using the same rules as are used for pull requests.
+type LocalChecker interface {
+	// Checks all the files in a folder and its sub-folders.
+	// Returns the problems found, and the files which were skipped because they are binary or generated.
+	// Paths in the check errors and skipped files are relative to the folder.
+	CheckDirectory(directory string) ([]checkTypes.CheckError, []checkTypes.SkippedFile, error)
+
+	// Checks all the files in a folder and its sub-folders, correcting those which can be fixed.
+	// Returns the paths of the files which were fixed, and the problems which could not be fixed.
+	FixDirectory(directory string) ([]string, []checkTypes.CheckError, error)
+}
+
+type LocalCheckerImpl struct {
+	console Console
+}
+
+func NewLocalChecker(console Console) (LocalChecker, error) {
+	var err error = nil
+	this := new(LocalCheckerImpl)
+	this.console = console
+	return this, err
+}
+
+func (this *LocalCheckerImpl) CheckDirectory(directory string) ([]checkTypes.CheckError, []checkTypes.SkippedFile, error) {
+	var err error = nil
+	checkErrors := make([]checkTypes.CheckError, 0)
+	skippedFiles := make([]checkTypes.SkippedFile, 0)
+
+	var policy *Policy
+	policy, err = this.getPolicy(directory)
+
+	files := NewLocalFileReader(directory)
+
+	var reuse *Reuse
+	if err == nil {
+		reuse, err = NewReuse(files, policy)
+	}
+
+	var classifier *FileClassifier
+	if err == nil {
+		classifier, err = NewFileClassifier(files)
+	}
+
+	var copyrightIgnore *CopyrightIgnore
+	if err == nil {
+		copyrightIgnore, err = NewCopyrightIgnore(files)
+	}
+
+	if err == nil {
+		err = filepath.WalkDir(directory, func(path string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+
+			if entry.IsDir() && entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			var relativePath string
+			relativePath, walkErr = filepath.Rel(directory, path)
+			relativePath = filepath.ToSlash(relativePath)
+
+			if walkErr != nil || path == directory {
+				return walkErr
+			}
+
+			if copyrightIgnore.IsIgnored(relativePath, entry.IsDir()) {
+				log.Printf("%s is not checked because %s ignores it.\n", relativePath, COPYRIGHT_IGNORE_FILE_PATH)
+				if entry.IsDir() {
+					// Nothing inside an ignored folder can be brought back, so don't look inside it.
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !entry.IsDir() {
+				checkError, skippedFile := this.checkFile(policy, classifier, path, relativePath)
+				if skippedFile != nil {
+					skippedFiles = append(skippedFiles, *skippedFile)
+				}
+				if checkError != nil {
+					checkError = reuse.ResolveCheckError(checkError)
+				}
+				if checkError != nil {
+					checkErrors = appendCheckError(checkErrors, checkError)
+				}
+			}
+			return walkErr
+		})
+	}
+
+	return checkErrors, skippedFiles, err
+}
+
+func (this *LocalCheckerImpl) FixDirectory(directory string) ([]string, []checkTypes.CheckError, error) {
+	fixedPaths := make([]string, 0)
+	unfixedCheckErrors := make([]checkTypes.CheckError, 0)
+
+	checkErrors, _, err := this.CheckDirectory(directory)
+	if err == nil {
+		for _, checkError := range checkErrors {
+			if checkError.Fix == nil {
+				unfixedCheckErrors = append(unfixedCheckErrors, checkError)
+			} else {
+				path := filepath.Join(directory, filepath.FromSlash(checkError.Path))
+
+				// Writing to the existing file keeps its permissions, so scripts stay executable.
+				err = os.WriteFile(path, []byte(checkError.Fix.FixedContent), 0644)
+				if err != nil {
+					break
+				}
+				log.Printf("Fixed %s\n", checkError.Path)
+				fixedPaths = append(fixedPaths, checkError.Path)
+			}
+		}
+	}
+
+	return fixedPaths, unfixedCheckErrors, err
+}
+
+func (this *LocalCheckerImpl) checkFile(policy *Policy, classifier *FileClassifier, path string, relativePath string) (*checkTypes.CheckError, *checkTypes.SkippedFile) {
+	return checkFileWithPolicy(relativePath, policy, classifier, func() (string, error) {
+		contentBytes, err := os.ReadFile(path)
+		if err != nil {
+			err = errors.New(fmt.Sprintf("Failed to read the file for checking - %v", err))
+		}
+		return string(contentBytes), err
+	})
+}
+
+// Reads the policy file from the folder, if there is one.
+func (this *LocalCheckerImpl) getPolicy(directory string) (*Policy, error) {
+	var err error = nil
+	var policy *Policy
+
+	policyPath := filepath.Join(directory, filepath.FromSlash(POLICY_FILE_PATH))
+
+	var content []byte
+	content, err = os.ReadFile(policyPath)
+	if err == nil {
+		log.Printf("Using policy file %s\n", policyPath)
+		policy, err = NewPolicyFromYaml(string(content))
+	} else if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+		policy = NewDefaultPolicy()
+	}
+
+	return policy, err
+}
+
+// Writes the names of the files which were fixed to the console.
+func ReportFixedFiles(console Console, fixedPaths []string) {
+	for _, path := range fixedPaths {
+		console.Write(fmt.Sprintf("Fixed %s\n", path))
+	}
+	console.Write(fmt.Sprintf("Fixed %d file(s).\n", len(fixedPaths)))
+}
+
+// Writes the names of the files which were skipped to the console, with the reason for each.
+func ReportSkippedFiles(console Console, skippedFiles []checkTypes.SkippedFile) {
+	for _, skippedFile := range skippedFiles {
+		if skippedFile.IsSuppressed {
+			console.Write(fmt.Sprintf("%s: suppressed - %s\n", skippedFile.Path, skippedFile.Reason))
+		} else {
+			console.Write(fmt.Sprintf("%s: skipped - %s\n", skippedFile.Path, skippedFile.Reason))
+		}
+	}
+	if len(skippedFiles) > 0 {
+		console.Write(fmt.Sprintf("Skipped %d file(s).\n\n", len(skippedFiles)))
+	}
+}
+
+// Writes the check errors to the console, in a form which editors can usually link to.
+// eg: "src/MyClass.java:3: Comment block containing copyright should be at the top of the file."
+func ReportCheckErrors(console Console, checkErrors []checkTypes.CheckError) {
+	for _, checkError := range checkErrors {
+		line := checkError.StartLine
+		if line < 1 {
+			line = 1
+		}
+		console.Write(fmt.Sprintf("%s:%d: %s\n\n", checkError.Path, line, checkError.Message))
+	}
+
+	if len(checkErrors) > 0 {
+		console.Write(fmt.Sprintf("Found %d problem(s).\n", len(checkErrors)))
+	} else {
+		console.Write("No problems found.\n")
+	}
+}
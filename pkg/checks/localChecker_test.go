@@ -0,0 +1,418 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const goodJavaContent = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package dev.galasa;
+`
+
+func writeTestFile(t *testing.T, directory string, relativePath string, content string) {
+	path := filepath.Join(directory, filepath.FromSlash(relativePath))
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	assert.Nil(t, err)
+	err = os.WriteFile(path, []byte(content), 0644)
+	assert.Nil(t, err)
+}
+
+func TestLocalCheckOfGoodFilesFindsNoProblems(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "src/main/java/MyClass.java", goodJavaContent)
+	writeTestFile(t, directory, "README.txt", "Not checked")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Empty(t, checkErrors)
+}
+
+func TestLocalCheckFindsProblemsInSubFolders(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "src/main/java/MyClass.java", goodJavaContent)
+	writeTestFile(t, directory, "src/main/java/BadClass.java", "package dev.galasa;\n")
+	writeTestFile(t, directory, "deploy/config.yaml", "key: value\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(checkErrors))
+	assert.Equal(t, "deploy/config.yaml", checkErrors[0].Path)
+	assert.Equal(t, "src/main/java/BadClass.java", checkErrors[1].Path)
+}
+
+func TestLocalCheckFindsProblemsInFilesWithoutExtension(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "images/Dockerfile", "FROM alpine\n")
+	writeTestFile(t, directory, "bin/build", "#!/usr/bin/env bash\necho hello\n")
+	writeTestFile(t, directory, "bin/awkscript", "#!/usr/bin/awk -f\n")
+	writeTestFile(t, directory, "LICENSE", "Eclipse Public License - v 2.0\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(checkErrors))
+	assert.Equal(t, "bin/build", checkErrors[0].Path)
+	assert.Equal(t, "images/Dockerfile", checkErrors[1].Path)
+}
+
+func TestLocalCheckIgnoresGitFolder(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, ".git/hooks/pre-commit.sh", "echo hello\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Empty(t, checkErrors)
+}
+
+func TestLocalCheckUsesPolicyFileInFolder(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, ".github/copyright.yaml", `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+exclude:
+  - "generated/"
+`)
+	writeTestFile(t, directory, "generated/Model.java", "package dev.galasa;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Empty(t, checkErrors)
+}
+
+func TestLocalCheckOfMissingFolderGivesError(t *testing.T) {
+	// Given
+	directory := filepath.Join(t.TempDir(), "does-not-exist")
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	_, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.NotNil(t, err)
+}
+
+func TestReportCheckErrorsWritesFileAndLine(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "BadClass.java", "leading text\n"+goodJavaContent)
+	checker, _ := NewLocalChecker(NewConsoleMock())
+	checkErrors, _, _ := checker.CheckDirectory(directory)
+	console := NewConsoleMock()
+
+	// When..
+	ReportCheckErrors(console, checkErrors)
+
+	// Then...
+	assert.True(t, console.contains("BadClass.java:1: Comment block containing copyright should be at the top of the file."))
+	assert.True(t, console.contains("Found 1 problem(s)."))
+}
+
+func TestLocalFixCorrectsFilesAndReportsThem(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "src/MyClass.java", goodJavaContent)
+	writeTestFile(t, directory, "src/BadClass.java", "package dev.galasa;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	fixedPaths, checkErrors, err := checker.FixDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Empty(t, checkErrors)
+	assert.Equal(t, []string{"src/BadClass.java"}, fixedPaths)
+
+	fixedContent, _ := os.ReadFile(filepath.Join(directory, "src", "BadClass.java"))
+	assert.Equal(t, goodJavaContent, string(fixedContent))
+
+	// And a second check finds nothing wrong.
+	checkErrors, _, _ = checker.CheckDirectory(directory)
+	assert.Empty(t, checkErrors)
+}
+
+func TestLocalFixKeepsScriptsExecutable(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "build.sh", "#!/bin/bash\necho hello\n")
+	path := filepath.Join(directory, "build.sh")
+	os.Chmod(path, 0755)
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	fixedPaths, _, err := checker.FixDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"build.sh"}, fixedPaths)
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestLocalCheckAcceptsFilesCoveredByReuse(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "images/logo.svg", "<svg></svg>\n")
+	writeTestFile(t, directory, "images/logo.svg.license", "Copyright contributors to the Galasa project\n\nSPDX-License-Identifier: EPL-2.0\n")
+	writeTestFile(t, directory, "docs/diagram.svg", "<svg></svg>\n")
+	writeTestFile(t, directory, "REUSE.toml", "#\n# Copyright contributors to the Galasa project\n#\n# SPDX-License-Identifier: EPL-2.0\n#\nversion = 1\n\n[[annotations]]\npath = \"docs/**\"\nSPDX-FileCopyrightText = \"Contributors to the Galasa project\"\nSPDX-License-Identifier = \"EPL-2.0\"\n")
+	writeTestFile(t, directory, "other/icon.svg", "<svg></svg>\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.Equal(t, "other/icon.svg", checkErrors[0].Path)
+}
+
+func TestLocalCheckReportsWrongSidecarFileOnce(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "images/logo.svg", "<svg></svg>\n")
+	writeTestFile(t, directory, "images/logo.svg.license", "SPDX-License-Identifier: MIT\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.Equal(t, "images/logo.svg.license", checkErrors[0].Path)
+}
+
+func TestLocalCheckSkipsBinaryAndGeneratedFiles(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, ".gitattributes", "dist/** linguist-generated\n")
+	writeTestFile(t, directory, "dist/bundle.js", "!function(){}();\n")
+	writeTestFile(t, directory, "api/service.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n")
+	writeTestFile(t, directory, "images/logo.svg", "<svg>\x00</svg>\n")
+	writeTestFile(t, directory, "src/main.js", "console.log('hello');\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, skippedFiles, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.Equal(t, "src/main.js", checkErrors[0].Path)
+
+	assert.Equal(t, 3, len(skippedFiles))
+	assert.Equal(t, "api/service.pb.go", skippedFiles[0].Path)
+	assert.Equal(t, "dist/bundle.js", skippedFiles[1].Path)
+	assert.Equal(t, "marked linguist-generated in .gitattributes", skippedFiles[1].Reason)
+	assert.Equal(t, "images/logo.svg", skippedFiles[2].Path)
+	assert.Equal(t, "binary content", skippedFiles[2].Reason)
+}
+
+func TestLocalCheckLeavesOutFilesInCopyrightIgnore(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, ".copyrightignore", "vendor/\n*.fixture.java\n!keep.fixture.java\n")
+	writeTestFile(t, directory, "vendor/lib/lib.go", "package lib\n")
+	writeTestFile(t, directory, "src/test/bad.fixture.java", "package dev.galasa;\n")
+	writeTestFile(t, directory, "src/test/keep.fixture.java", "package dev.galasa;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.Equal(t, "src/test/keep.fixture.java", checkErrors[0].Path)
+}
+
+func TestLocalCheckReportsSuppressedFiles(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "src/Ignored.java", "// copyright-check: ignore\npackage dev.galasa;\n")
+	writeTestFile(t, directory, "src/Upstream.java", "/*\n * Copyright 2019 Someone Else\n *\n * SPDX-License-Identifier: Apache-2.0\n */\n// copyright-check: allow-license Apache-2.0\npackage org.other;\n")
+	writeTestFile(t, directory, "src/Wrong.java", "/*\n * SPDX-License-Identifier: MIT\n */\n// copyright-check: allow-license Apache-2.0\npackage org.other;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, skippedFiles, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.Equal(t, "src/Wrong.java", checkErrors[0].Path)
+
+	assert.Equal(t, 2, len(skippedFiles))
+	assert.Equal(t, "src/Ignored.java", skippedFiles[0].Path)
+	assert.True(t, skippedFiles[0].IsSuppressed)
+	assert.Equal(t, "copyright-check: ignore", skippedFiles[0].Reason)
+	assert.Equal(t, "src/Upstream.java", skippedFiles[1].Path)
+	assert.Equal(t, "copyright-check: allow-license Apache-2.0", skippedFiles[1].Reason)
+}
+
+func TestLocalCheckReportsDirectiveForUnknownLicenseRatherThanThirdPartyCode(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "src/Upstream.java", "/*\n * Copyright 2019 Someone Else\n *\n * SPDX-License-Identifier: Apache-2.0\n */\n// copyright-check: allow-license Apache2\npackage org.other;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, skippedFiles, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Empty(t, skippedFiles)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.False(t, checkErrors[0].IsThirdParty)
+	assert.Nil(t, checkErrors[0].Fix)
+	assert.Contains(t, checkErrors[0].Message, "The copyright-check directive allows licence 'Apache2', which is not valid")
+}
+
+func TestLocalFixLeavesThirdPartyCodeAlone(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	copiedContent := "/*\n * Copyright 2019 Acme Corp\n *\n * SPDX-License-Identifier: Apache-2.0\n */\npackage com.acme;\n"
+	writeTestFile(t, directory, "src/Copied.java", copiedContent)
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	fixedPaths, checkErrors, err := checker.FixDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Empty(t, fixedPaths)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.True(t, checkErrors[0].IsThirdParty)
+
+	content, _ := os.ReadFile(filepath.Join(directory, "src", "Copied.java"))
+	assert.Equal(t, copiedContent, string(content))
+}
+
+func TestLocalCheckFindsLegacyHeaderOfAnotherHolderIsThirdPartyCode(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, ".github/copyright.yaml", `#
+# Copyright Acme Corp
+#
+# SPDX-License-Identifier: MIT
+#
+holder: Copyright Acme Corp
+license: MIT
+`)
+	writeTestFile(t, directory, "src/Old.java", "/*\n * (c) Copyright IBM Corp. 2019.\n */\npackage com.acme;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.True(t, checkErrors[0].IsThirdParty)
+	assert.Contains(t, checkErrors[0].Message, "Third-party code: needs legal review. Found copyright 'Copyright IBM Corp. 2019.'")
+}
+
+func TestLocalFixMigratesLegacyHeaderWithoutPolicyFile(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, "src/Old.java", "/*\n * Licensed Materials - Property of IBM\n *\n * (c) Copyright IBM Corp. 2019, 2021.\n */\npackage dev.galasa;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	checkErrors, _, err := checker.CheckDirectory(directory)
+	fixedPaths, unfixedCheckErrors, fixErr := checker.FixDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(checkErrors))
+	assert.False(t, checkErrors[0].IsThirdParty)
+	assert.Contains(t, checkErrors[0].Message, "Found a legacy copyright header, which should be migrated to the current one. Replace lines 2-4:")
+
+	assert.Nil(t, fixErr)
+	assert.Empty(t, unfixedCheckErrors)
+	assert.Equal(t, []string{"src/Old.java"}, fixedPaths)
+	fixedContent, _ := os.ReadFile(filepath.Join(directory, "src", "Old.java"))
+	assert.Equal(t, goodJavaContent, string(fixedContent))
+}
+
+func TestLocalFixMigratesLegacyHeaderListedInPolicy(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	writeTestFile(t, directory, ".github/copyright.yaml", `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+legacyHeaders:
+  - '(?i)copyright\s+acme corp'
+`)
+	writeTestFile(t, directory, "src/Old.java", "/*\n * Copyright Acme Corp. 2015\n */\npackage dev.galasa;\n")
+	writeTestFile(t, directory, "src/Older.java", "/*\n * (c) Copyright IBM Corp. 2019.\n */\npackage dev.galasa;\n")
+
+	checker, _ := NewLocalChecker(NewConsoleMock())
+
+	// When..
+	fixedPaths, unfixedCheckErrors, err := checker.FixDirectory(directory)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Empty(t, unfixedCheckErrors)
+	assert.ElementsMatch(t, []string{"src/Old.java", "src/Older.java"}, fixedPaths)
+	fixedContent, _ := os.ReadFile(filepath.Join(directory, "src", "Old.java"))
+	assert.Equal(t, goodJavaContent, string(fixedContent))
+}
@@ -0,0 +1,356 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/fileCheckers"
+	"github.com/galasa-dev/githubapp-copyright/pkg/spdx"
+	"gopkg.in/yaml.v3"
+)
+
+// Where in a repository we look for the policy which controls how that repository is checked.
+const POLICY_FILE_PATH = ".github/copyright.yaml"
+
+// Used to turn off checking for an extension which is checked by default.
+const POLICY_CHECKER_KIND_NONE = "none"
+
+// The layout of the .github/copyright.yaml file. eg:
+//
+//	holder: Copyright contributors to the Galasa project
+//	license: EPL-2.0
+//	licenses:
+//	  - EPL-2.0 OR Apache-2.0
+//	legacyHeaders:
+//	  - '(?i)copyright\s+acme corp'
+//	checkers:
+//	  .rb: hash
+//	  .js: none
+//	filenames:
+//	  Vagrantfile: hash
+//	include:
+//	  - "src/**"
+//	exclude:
+//	  - "vendor/"
+//	autofix: true
+//	suggestions: true
+//
+// Anything not specified takes the default value.
+type PolicyFile struct {
+	Holder   string            `yaml:"holder"`
+	License  string            `yaml:"license"`
+	Checkers map[string]string `yaml:"checkers"`
+
+	// Other SPDX licence expressions which headers can have instead of the license. eg: "EPL-2.0 OR Apache-2.0"
+	Licenses []string `yaml:"licenses"`
+
+	// Regular expressions for the lines of old headers, which are migrated to the current header. eg: "(?i)copyright acme corp"
+	// These are as well as the IBM headers, which are always migrated to the Galasa header.
+	LegacyHeaders []string `yaml:"legacyHeaders"`
+
+	// Which kind of header to expect in files with particular names, whatever their extension. eg: "Dockerfile.*"
+	FileNames map[string]string `yaml:"filenames"`
+
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+
+	// Should the app push a commit which fixes the headers to a pull request branch in the same repository ?
+	AutoFix bool `yaml:"autofix"`
+
+	// Should the app review failing pull requests, suggesting changes which fix the headers ?
+	Suggestions bool `yaml:"suggestions"`
+}
+
+// Controls which files in a repository are checked, and what they are checked for.
+type Policy struct {
+	header fileCheckers.CopyrightHeader
+
+	// The index is the file extension (including the dot) eg: ".java"
+	// The value is the file checker which will be used.
+	checkersByExtension map[string]fileCheckers.FileChecker
+
+	// The index is a file name, which can have * and ? wildcards. eg: "Dockerfile.*"
+	// These take priority over the extension of the file.
+	checkersByFileName map[string]fileCheckers.FileChecker
+
+	// The file names to try, with the ones without wildcards first so they win.
+	// A name with no checker means files with that name are not checked.
+	fileNamePatterns []string
+
+	// The index is the program named on the #! line of a script without an extension. eg: "bash"
+	checkersByInterpreter map[string]fileCheckers.FileChecker
+
+	// If there are any includes, a file must match at least one of them to be checked.
+	includes []*Glob
+
+	// A file which matches any of the excludes is not checked.
+	excludes []*Glob
+
+	isAutoFixEnabled     bool
+	isSuggestionsEnabled bool
+}
+
+// The policy used for repositories which don't have a policy file of their own.
+func NewDefaultPolicy() *Policy {
+	policy, _ := NewPolicy(PolicyFile{})
+	return policy
+}
+
+// Parses the content of a policy file.
+func NewPolicyFromYaml(content string) (*Policy, error) {
+	var err error = nil
+	var policy *Policy = nil
+	var policyFile PolicyFile
+
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&policyFile)
+	if err == io.EOF {
+		// An empty file. Everything takes the default value.
+		err = nil
+	}
+
+	if err == nil {
+		policy, err = NewPolicy(policyFile)
+	}
+
+	if err != nil {
+		err = errors.New(fmt.Sprintf("Invalid policy file %s - %s", POLICY_FILE_PATH, err.Error()))
+	}
+
+	return policy, err
+}
+
+func NewPolicy(policyFile PolicyFile) (*Policy, error) {
+	var err error = nil
+
+	this := new(Policy)
+
+	this.isAutoFixEnabled = policyFile.AutoFix
+	this.isSuggestionsEnabled = policyFile.Suggestions
+
+	this.header = fileCheckers.NewDefaultCopyrightHeader()
+	if policyFile.Holder != "" {
+		this.header.Holder = policyFile.Holder
+	}
+	if policyFile.License != "" {
+		this.header.LicenseId = policyFile.License
+	}
+	this.header.OtherLicenseIds = policyFile.Licenses
+
+	err = checkLicenseExpressions(append([]string{this.header.LicenseId}, this.header.OtherLicenseIds...))
+
+	if err == nil {
+		var legacyLinePatterns []*regexp.Regexp
+		legacyLinePatterns, err = newLegacyLinePatterns(policyFile.LegacyHeaders)
+		this.header.LegacyLinePatterns = append(fileCheckers.GetBuiltInLegacyLinePatterns(this.header.Holder), legacyLinePatterns...)
+	}
+
+	checkerKindsByExtension := fileCheckers.GetDefaultCheckerKindsByExtension()
+	for extension, kind := range policyFile.Checkers {
+		if !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
+		}
+		checkerKindsByExtension[extension] = kind
+	}
+
+	checkerKindsByFileName := fileCheckers.GetDefaultCheckerKindsByFileName()
+	for fileName, kind := range policyFile.FileNames {
+		checkerKindsByFileName[fileName] = kind
+	}
+
+	// Files with the same kind share the same checker.
+	checkersByKind := make(map[string]fileCheckers.FileChecker)
+
+	if err == nil {
+		this.checkersByExtension, err = this.createCheckers("extension", checkerKindsByExtension, checkersByKind)
+	}
+
+	if err == nil {
+		this.checkersByFileName, err = this.createCheckers("file name", checkerKindsByFileName, checkersByKind)
+	}
+
+	if err == nil {
+		this.fileNamePatterns, err = sortFileNamePatterns(checkerKindsByFileName)
+	}
+
+	if err == nil {
+		this.checkersByInterpreter, err = this.createCheckers("interpreter", fileCheckers.GetDefaultCheckerKindsByInterpreter(), checkersByKind)
+	}
+
+	if err == nil {
+		this.includes, err = newGlobs(policyFile.Include)
+	}
+
+	if err == nil {
+		this.excludes, err = newGlobs(policyFile.Exclude)
+	}
+
+	return this, err
+}
+
+// Checks each licence in the policy is a valid SPDX licence expression made of known identifiers.
+func checkLicenseExpressions(licenses []string) error {
+	var err error = nil
+	for _, license := range licenses {
+		_, err = spdx.ParseExpression(license)
+		if err != nil {
+			err = errors.New(fmt.Sprintf("license '%s': %s", license, err.Error()))
+			break
+		}
+	}
+	return err
+}
+
+// Compiles the patterns for the lines of legacy headers.
+func newLegacyLinePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var err error = nil
+	legacyLinePatterns := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		var legacyLinePattern *regexp.Regexp
+		legacyLinePattern, err = regexp.Compile(pattern)
+		if err != nil {
+			err = errors.New(fmt.Sprintf("bad legacy header pattern '%s': %s", pattern, err.Error()))
+			break
+		}
+		legacyLinePatterns = append(legacyLinePatterns, legacyLinePattern)
+	}
+	return legacyLinePatterns, err
+}
+
+// Creates the checkers for a map of kinds, re-using any checker already made for the same kind.
+// The index of the map is whatever the checkers are looked up by, which the description says. eg: "extension"
+func (this *Policy) createCheckers(indexDescription string, checkerKinds map[string]string, checkersByKind map[string]fileCheckers.FileChecker) (map[string]fileCheckers.FileChecker, error) {
+	var err error = nil
+	checkers := make(map[string]fileCheckers.FileChecker)
+
+	for index, kind := range checkerKinds {
+		if kind != POLICY_CHECKER_KIND_NONE {
+			checker, isCreated := checkersByKind[kind]
+			if !isCreated {
+				checker, err = fileCheckers.NewFileCheckerOfKind(kind, this.header)
+				if err != nil {
+					err = errors.New(fmt.Sprintf("%s %s: %s", indexDescription, index, err.Error()))
+					break
+				}
+				checkersByKind[kind] = checker
+			}
+			checkers[index] = checker
+		}
+	}
+	return checkers, err
+}
+
+// Checks the file name patterns are valid, and puts them in the order they should be tried.
+// Names without wildcards come first, so "Dockerfile" wins over "Docker*".
+func sortFileNamePatterns(checkerKindsByFileName map[string]string) ([]string, error) {
+	var err error = nil
+	patterns := make([]string, 0, len(checkerKindsByFileName))
+
+	for pattern := range checkerKindsByFileName {
+		_, err = path.Match(pattern, "")
+		if err != nil {
+			err = errors.New(fmt.Sprintf("bad file name pattern '%s': %s", pattern, err.Error()))
+			break
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		isWildcardI := strings.ContainsAny(patterns[i], "*?[")
+		isWildcardJ := strings.ContainsAny(patterns[j], "*?[")
+		if isWildcardI != isWildcardJ {
+			return !isWildcardI
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	return patterns, err
+}
+
+func newGlobs(patterns []string) ([]*Glob, error) {
+	var err error = nil
+	globs := make([]*Glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		var glob *Glob
+		glob, err = NewGlob(pattern)
+		if err != nil {
+			err = errors.New(fmt.Sprintf("bad pattern '%s': %s", pattern, err.Error()))
+			break
+		}
+		globs = append(globs, glob)
+	}
+	return globs, err
+}
+
+// Gets the file checker to use for a file, based on its name or else its extension.
+// Returns nil if files like that are not checked.
+func (this *Policy) GetFileChecker(filePath string) fileCheckers.FileChecker {
+	var checker fileCheckers.FileChecker = nil
+
+	pattern, isNamed := this.findFileNamePattern(filePath)
+	if isNamed {
+		checker = this.checkersByFileName[pattern]
+	} else {
+		checker = this.checkersByExtension[extractFileExtension(filePath)]
+	}
+
+	return checker
+}
+
+// Could the file be a script, which is checked based on the #! line at the top of it ?
+// Only files without an extension, which aren't named in the policy, are treated as scripts.
+func (this *Policy) IsPossibleScript(filePath string) bool {
+	_, isNamed := this.findFileNamePattern(filePath)
+	return extractFileExtension(filePath) == "" && !isNamed
+}
+
+// Finds the first file name pattern which matches the name of the file, if there is one.
+func (this *Policy) findFileNamePattern(filePath string) (string, bool) {
+	baseFileName := extractBaseFileName(filePath)
+	for _, pattern := range this.fileNamePatterns {
+		isMatched, _ := path.Match(pattern, baseFileName)
+		if isMatched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// Gets the file checker to use for a script, based on the program named on its #! line.
+// Returns nil if the content isn't a script, or not one which is checked.
+func (this *Policy) GetFileCheckerForScript(content string) fileCheckers.FileChecker {
+	return this.checkersByInterpreter[fileCheckers.GetShebangInterpreter(content)]
+}
+
+// Does the policy say this file should be checked, based on its path ?
+func (this *Policy) IsFileIncluded(path string) bool {
+	isIncluded := true
+	if len(this.includes) > 0 {
+		isIncluded = matchesAnyGlob(this.includes, path)
+	}
+	if isIncluded {
+		isIncluded = !matchesAnyGlob(this.excludes, path)
+	}
+	return isIncluded
+}
+
+// Should we push commits which fix the headers to pull request branches ?
+func (this *Policy) IsAutoFixEnabled() bool {
+	return this.isAutoFixEnabled
+}
+
+// Should we review failing pull requests with suggested changes which fix the headers ?
+func (this *Policy) IsSuggestionsEnabled() bool {
+	return this.isSuggestionsEnabled
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicyChecksJavaAndYaml(t *testing.T) {
+	policy := NewDefaultPolicy()
+	assert.NotNil(t, policy.GetFileChecker(".java"))
+	assert.NotNil(t, policy.GetFileChecker(".yaml"))
+	assert.Nil(t, policy.GetFileChecker(".txt"))
+	assert.True(t, policy.IsFileIncluded("src/main/java/MyClass.java"))
+}
+
+func TestEmptyPolicyFileGivesDefaultPolicy(t *testing.T) {
+	policy, err := NewPolicyFromYaml("")
+	assert.Nil(t, err)
+	assert.Equal(t, "Copyright contributors to the Galasa project", policy.header.Holder)
+	assert.Equal(t, "EPL-2.0", policy.header.LicenseId)
+	assert.NotNil(t, policy.GetFileChecker(".go"))
+}
+
+func TestPolicyCanSetHolderAndLicense(t *testing.T) {
+	policy, err := NewPolicyFromYaml(`
+holder: Copyright Acme Corp.
+license: Apache-2.0
+`)
+	assert.Nil(t, err)
+
+	checkError := policy.GetFileChecker(".java").CheckFileContent(`/*
+ * Copyright Acme Corp.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+`, "test.java")
+	assert.Nil(t, checkError)
+}
+
+func TestPolicyCanAddAndRemoveExtensions(t *testing.T) {
+	policy, err := NewPolicyFromYaml(`
+checkers:
+  .py: hash
+  js: none
+`)
+	assert.Nil(t, err)
+	assert.NotNil(t, policy.GetFileChecker(".py"))
+	assert.Nil(t, policy.GetFileChecker(".js"))
+	assert.NotNil(t, policy.GetFileChecker(".java"))
+}
+
+func TestDefaultPolicyChecksFilesByName(t *testing.T) {
+	policy := NewDefaultPolicy()
+	assert.NotNil(t, policy.GetFileChecker("Dockerfile"))
+	assert.NotNil(t, policy.GetFileChecker("images/base/Dockerfile.alpine"))
+	assert.NotNil(t, policy.GetFileChecker("Makefile"))
+	assert.Equal(t, policy.GetFileChecker("build.groovy"), policy.GetFileChecker("Jenkinsfile"))
+	assert.Nil(t, policy.GetFileChecker("LICENSE"))
+}
+
+func TestPolicyFileNameTakesPriorityOverExtension(t *testing.T) {
+	policy, err := NewPolicyFromYaml(`
+filenames:
+  "*.generated.java": none
+  "build.*": hash
+`)
+	assert.Nil(t, err)
+	assert.NotNil(t, policy.GetFileChecker("src/MyClass.java"))
+	assert.Nil(t, policy.GetFileChecker("src/MyClass.generated.java"))
+	assert.Equal(t, policy.GetFileChecker("deploy/config.yaml"), policy.GetFileChecker("build.gradle"))
+}
+
+func TestPolicyFileNameWithoutWildcardWinsOverPattern(t *testing.T) {
+	policy, err := NewPolicyFromYaml(`
+filenames:
+  "Docker*": none
+`)
+	assert.Nil(t, err)
+	assert.NotNil(t, policy.GetFileChecker("Dockerfile"))
+	assert.Nil(t, policy.GetFileChecker("Dockerfile-old"))
+}
+
+func TestPolicyWithBadFileNamePatternGivesError(t *testing.T) {
+	_, err := NewPolicyFromYaml(`
+filenames:
+  "[Dockerfile": hash
+`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "bad file name pattern '[Dockerfile'")
+}
+
+func TestOnlyFilesWithoutExtensionOrCheckerArePossibleScripts(t *testing.T) {
+	policy := NewDefaultPolicy()
+	assert.True(t, policy.IsPossibleScript("bin/build"))
+	assert.False(t, policy.IsPossibleScript("Makefile"))
+	assert.False(t, policy.IsPossibleScript("notes.txt"))
+}
+
+func TestPolicyChoosesCheckerForScriptFromShebang(t *testing.T) {
+	policy := NewDefaultPolicy()
+	assert.Equal(t, policy.GetFileChecker("build.sh"), policy.GetFileCheckerForScript("#!/bin/bash\n"))
+	assert.Equal(t, policy.GetFileChecker("tool.py"), policy.GetFileCheckerForScript("#!/usr/bin/env python3\n"))
+	assert.Nil(t, policy.GetFileCheckerForScript("#!/usr/bin/awk -f\n"))
+	assert.Nil(t, policy.GetFileCheckerForScript("Some licence text\n"))
+}
+
+func TestMarkdownIsOnlyCheckedWhenPolicyAsks(t *testing.T) {
+	assert.Nil(t, NewDefaultPolicy().GetFileChecker("docs/index.md"))
+
+	policy, err := NewPolicyFromYaml(`
+checkers:
+  .md: markdown
+  .mdx: markdown
+`)
+	assert.Nil(t, err)
+	assert.NotNil(t, policy.GetFileChecker("docs/index.md"))
+	assert.NotNil(t, policy.GetFileChecker("docs/index.mdx"))
+}
+
+func TestPolicyWithUnknownCheckerKindGivesError(t *testing.T) {
+	_, err := NewPolicyFromYaml(`
+checkers:
+  .py: snake
+`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Unknown file checker kind 'snake'")
+}
+
+func TestPolicyWithUnknownFieldGivesError(t *testing.T) {
+	_, err := NewPolicyFromYaml(`
+holdr: Copyright Acme Corp.
+`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Invalid policy file .github/copyright.yaml")
+}
+
+func TestPolicyIncludesAndExcludesFiles(t *testing.T) {
+	policy, err := NewPolicyFromYaml(`
+include:
+  - "src/**"
+exclude:
+  - "**/generated/**"
+`)
+	assert.Nil(t, err)
+	assert.True(t, policy.IsFileIncluded("src/main/MyClass.java"))
+	assert.False(t, policy.IsFileIncluded("docs/index.js"))
+	assert.False(t, policy.IsFileIncluded("src/generated/Model.java"))
+}
+
+func TestAutoFixIsOffByDefault(t *testing.T) {
+	policy := NewDefaultPolicy()
+	assert.False(t, policy.IsAutoFixEnabled())
+}
+
+func TestPolicyCanTurnOnAutoFix(t *testing.T) {
+	policy, err := NewPolicyFromYaml("autofix: true\n")
+	assert.Nil(t, err)
+	assert.True(t, policy.IsAutoFixEnabled())
+}
+
+func TestPolicyCanAllowOtherLicenseExpressions(t *testing.T) {
+	policy, err := NewPolicyFromYaml(`
+licenses:
+  - EPL-2.0 OR Apache-2.0
+`)
+	assert.Nil(t, err)
+
+	checkError := policy.GetFileChecker(".java").CheckFileContent(`/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: Apache-2.0 OR EPL-2.0
+ */
+`, "test.java")
+	assert.Nil(t, checkError)
+}
+
+func TestPolicyWithUnknownLicenseIsInvalid(t *testing.T) {
+	_, err := NewPolicyFromYaml(`
+license: EPL-2.0 OR Apache-3.0
+`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "license 'EPL-2.0 OR Apache-3.0': 'Apache-3.0' is not a known SPDX licence identifier")
+}
+
+func TestPolicyWithBadLegacyHeaderPatternGivesError(t *testing.T) {
+	_, err := NewPolicyFromYaml(`
+legacyHeaders:
+  - "copyright (ibm"
+`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "bad legacy header pattern 'copyright (ibm'")
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+	"github.com/galasa-dev/githubapp-copyright/pkg/fileCheckers"
+)
+
+// Where the REUSE specification says a repository can say who holds the copyright of files
+// which can't hold a header of their own. See https://reuse.software/spec/
+const (
+	REUSE_DEP5_FILE_PATH = ".reuse/dep5"
+	REUSE_TOML_FILE_PATH = "REUSE.toml"
+)
+
+// Says who holds the copyright of some files in a repository, and what their licence is.
+type ReuseAnnotation struct {
+	// The paths the annotation covers.
+	paths []*Glob
+
+	// eg: "2023 Contributors to the Galasa project"
+	copyrights []string
+
+	// eg: "EPL-2.0"
+	license string
+}
+
+// Decides whether files which failed the check are covered by REUSE information instead.
+// A file is covered if it has a sidecar .license file with the right header,
+// or if .reuse/dep5 or REUSE.toml gives it the right copyright and licence.
+type Reuse struct {
+	files          FileReader
+	header         fileCheckers.CopyrightHeader
+	sidecarChecker fileCheckers.FileChecker
+	annotations    []*ReuseAnnotation
+}
+
+// Reads the REUSE information of a repository. Repositories without any have no annotations.
+func NewReuse(files FileReader, policy *Policy) (*Reuse, error) {
+	var err error = nil
+
+	this := new(Reuse)
+	this.files = files
+	this.header = policy.header
+	this.sidecarChecker = fileCheckers.NewSidecarFileCheckerForHeader(policy.header)
+	this.annotations = make([]*ReuseAnnotation, 0)
+
+	var content string
+	var isFound bool
+	content, isFound, err = files.ReadFile(REUSE_DEP5_FILE_PATH)
+	if err == nil && isFound {
+		var annotations []*ReuseAnnotation
+		annotations, err = parseDep5(content)
+		if err == nil {
+			this.annotations = append(this.annotations, annotations...)
+		} else {
+			err = errors.New(fmt.Sprintf("Invalid REUSE file %s - %s", REUSE_DEP5_FILE_PATH, err.Error()))
+		}
+	}
+
+	if err == nil {
+		content, isFound, err = files.ReadFile(REUSE_TOML_FILE_PATH)
+		if err == nil && isFound {
+			var annotations []*ReuseAnnotation
+			annotations, err = parseReuseToml(content)
+			if err == nil {
+				this.annotations = append(this.annotations, annotations...)
+			} else {
+				err = errors.New(fmt.Sprintf("Invalid REUSE file %s - %s", REUSE_TOML_FILE_PATH, err.Error()))
+			}
+		}
+	}
+
+	return this, err
+}
+
+// Takes a problem found with a file, and decides whether the REUSE information makes up for it.
+// Returns nil if the file is covered, or the problem with its sidecar file if that is wrong.
+// Otherwise the problem is returned unchanged.
+// Only a missing header can be made up for. Other problems, such as failing to read the file, still count.
+func (this *Reuse) ResolveCheckError(checkError *checkTypes.CheckError) *checkTypes.CheckError {
+	path := checkError.Path
+
+	if !checkError.IsMissingHeader {
+		return checkError
+	}
+
+	if strings.HasSuffix(path, fileCheckers.SIDECAR_FILE_EXTENSION) {
+		// A sidecar file can't be covered by another one.
+		return checkError
+	}
+
+	// A sidecar file takes priority over everything else.
+	sidecarPath := path + fileCheckers.SIDECAR_FILE_EXTENSION
+	content, isFound, err := this.files.ReadFile(sidecarPath)
+	if err != nil {
+		log.Printf("Failed to read %s. Reason: %s\n", sidecarPath, err.Error())
+	} else if isFound {
+		log.Printf("File %s is covered by %s\n", path, sidecarPath)
+		checkError = checkFileContent(this.sidecarChecker, content, sidecarPath, this.header, nil)
+	} else {
+		annotation := this.findAnnotation(path)
+		if annotation != nil && annotation.isMatchingHeader(this.header) {
+			log.Printf("File %s is covered by REUSE annotations\n", path)
+			checkError = nil
+		}
+	}
+
+	return checkError
+}
+
+// Finds the annotation which applies to a path. If more than one covers it, the last one wins.
+func (this *Reuse) findAnnotation(path string) *ReuseAnnotation {
+	var found *ReuseAnnotation = nil
+	for _, annotation := range this.annotations {
+		for _, pathPattern := range annotation.paths {
+			if pathPattern.Matches(path) {
+				found = annotation
+			}
+		}
+	}
+	return found
+}
+
+// Does the annotation give the copyright and licence the header should have ?
+func (this *ReuseAnnotation) isMatchingHeader(header fileCheckers.CopyrightHeader) bool {
+	isMatched := false
+	if header.CheckLicense(this.license) == nil {
+		for _, copyright := range this.copyrights {
+			if fileCheckers.NormaliseCopyrightText(copyright) == fileCheckers.NormaliseCopyrightText(header.Holder) {
+				isMatched = true
+			}
+		}
+	}
+	return isMatched
+}
+
+// Parses a .reuse/dep5 file, which is in the debian machine-readable copyright format.
+// See https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+//
+//	Files: images/* docs/*.png
+//	Copyright: 2023 Contributors to the Galasa project
+//	License: EPL-2.0
+//
+// Paragraphs without a Files field, such as the header, are ignored.
+func parseDep5(content string) ([]*ReuseAnnotation, error) {
+	var err error = nil
+	annotations := make([]*ReuseAnnotation, 0)
+
+	for _, paragraph := range splitDep5Paragraphs(content) {
+		files, isFilesParagraph := paragraph["files"]
+		if isFilesParagraph {
+			annotation := new(ReuseAnnotation)
+
+			for _, pattern := range strings.Fields(files) {
+				var path *Glob
+				path, err = NewGlobWithOptions(pattern, dep5GlobOptions)
+				if err != nil {
+					err = errors.New(fmt.Sprintf("bad Files pattern '%s'", pattern))
+					break
+				}
+				annotation.paths = append(annotation.paths, path)
+			}
+			if err != nil {
+				break
+			}
+
+			for _, copyright := range strings.Split(paragraph["copyright"], "\n") {
+				if strings.TrimSpace(copyright) != "" {
+					annotation.copyrights = append(annotation.copyrights, strings.TrimSpace(copyright))
+				}
+			}
+
+			// Only the first line is the licence. Any more lines are the licence text.
+			annotation.license = strings.TrimSpace(strings.SplitN(paragraph["license"], "\n", 2)[0])
+
+			if annotation.license == "" {
+				err = errors.New(fmt.Sprintf("the paragraph for files '%s' has no License field", files))
+				break
+			}
+
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	return annotations, err
+}
+
+// Splits a dep5 file into paragraphs, each of which is a map of the fields in it.
+// The names of the fields are in lower case, as they aren't case sensitive.
+// Continuation lines start with whitespace, and are joined to the field they continue with a new line.
+func splitDep5Paragraphs(content string) []map[string]string {
+	paragraphs := make([]map[string]string, 0)
+	paragraph := make(map[string]string)
+	fieldName := ""
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, paragraph)
+				paragraph = make(map[string]string)
+			}
+			fieldName = ""
+		} else if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if fieldName != "" {
+				paragraph[fieldName] += "\n" + strings.TrimSpace(line)
+			}
+		} else if !strings.HasPrefix(line, "#") {
+			colon := strings.Index(line, ":")
+			if colon > 0 {
+				fieldName = strings.ToLower(strings.TrimSpace(line[:colon]))
+				paragraph[fieldName] = strings.TrimSpace(line[colon+1:])
+			}
+		}
+	}
+
+	if len(paragraph) > 0 {
+		paragraphs = append(paragraphs, paragraph)
+	}
+	return paragraphs
+}
@@ -0,0 +1,294 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parses a REUSE.toml file at the root of a repository. eg:
+//
+//	version = 1
+//
+//	[[annotations]]
+//	path = ["images/**", "docs/*.png"]
+//	SPDX-FileCopyrightText = "2023 Contributors to the Galasa project"
+//	SPDX-License-Identifier = "EPL-2.0"
+//
+// Only the parts of TOML which REUSE.toml files need are understood: [[annotations]] tables,
+// and keys with string, integer or array of string values.
+func parseReuseToml(content string) ([]*ReuseAnnotation, error) {
+	var err error = nil
+	annotations := make([]*ReuseAnnotation, 0)
+	var annotation *ReuseAnnotation = nil
+
+	parser := newTomlParser(content)
+	for err == nil && !parser.isFinished() {
+		var key string
+		var values []string
+		var isTable bool
+
+		isTable, key, values, err = parser.nextEntry()
+		if err == nil {
+			if isTable {
+				if key != "annotations" {
+					err = errors.New(fmt.Sprintf("line %d: unexpected table [[%s]]", parser.lineNumber, key))
+				} else {
+					annotation = new(ReuseAnnotation)
+					annotations = append(annotations, annotation)
+				}
+			} else if annotation != nil {
+				err = annotation.setTomlValue(key, values)
+				if err != nil {
+					err = errors.New(fmt.Sprintf("line %d: %s", parser.lineNumber, err.Error()))
+				}
+			}
+			// Keys before the first table, such as version, don't affect which files are covered.
+		}
+	}
+
+	if err == nil {
+		for index, annotation := range annotations {
+			if len(annotation.paths) == 0 || annotation.license == "" {
+				err = errors.New(fmt.Sprintf("annotation %d needs a path and an SPDX-License-Identifier", index+1))
+				break
+			}
+		}
+	}
+
+	return annotations, err
+}
+
+func (this *ReuseAnnotation) setTomlValue(key string, values []string) error {
+	var err error = nil
+	switch key {
+	case "path":
+		for _, pattern := range values {
+			var path *Glob
+			path, err = NewGlobWithOptions(pattern, reuseTomlGlobOptions)
+			if err != nil {
+				err = errors.New(fmt.Sprintf("bad path '%s'", pattern))
+				break
+			}
+			this.paths = append(this.paths, path)
+		}
+	case "SPDX-FileCopyrightText":
+		this.copyrights = append(this.copyrights, values...)
+	case "SPDX-License-Identifier":
+		if len(values) != 1 {
+			err = errors.New("SPDX-License-Identifier must be a single string")
+		} else {
+			this.license = values[0]
+		}
+	}
+	// Other keys, such as precedence, don't change which files are covered.
+	return err
+}
+
+// Reads the entries of a TOML file one at a time.
+type tomlParser struct {
+	content    string
+	offset     int
+	lineNumber int
+}
+
+func newTomlParser(content string) *tomlParser {
+	this := new(tomlParser)
+	this.content = strings.ReplaceAll(content, "\r\n", "\n")
+	this.lineNumber = 1
+	this.skipWhitespaceAndComments()
+	return this
+}
+
+func (this *tomlParser) isFinished() bool {
+	return this.offset >= len(this.content)
+}
+
+// Reads a [[table]] header, or a key = value line.
+// Single values are returned as an array of one, so callers can treat them alike.
+func (this *tomlParser) nextEntry() (isTable bool, key string, values []string, err error) {
+	if strings.HasPrefix(this.content[this.offset:], "[[") {
+		lineEnd := this.findLineEnd()
+		line := strings.TrimSpace(this.stripComment(this.content[this.offset:lineEnd]))
+		if !strings.HasSuffix(line, "]]") {
+			err = errors.New(fmt.Sprintf("line %d: bad table header", this.lineNumber))
+		} else {
+			isTable = true
+			key = strings.TrimSpace(line[2 : len(line)-2])
+			this.offset = lineEnd
+		}
+	} else {
+		key, err = this.readKey()
+		if err == nil {
+			values, err = this.readValue()
+		}
+		if err == nil {
+			this.skipSpaces()
+			lineEnd := this.findLineEnd()
+			if strings.TrimSpace(this.stripComment(this.content[this.offset:lineEnd])) != "" {
+				err = errors.New(fmt.Sprintf("line %d: unexpected text after the value of %s", this.lineNumber, key))
+			}
+			this.offset = lineEnd
+		}
+	}
+
+	if err == nil {
+		this.skipWhitespaceAndComments()
+	}
+	return isTable, key, values, err
+}
+
+func (this *tomlParser) readKey() (string, error) {
+	var err error = nil
+	var key string
+
+	equals := strings.Index(this.content[this.offset:], "=")
+	lineEnd := this.findLineEnd()
+	if equals < 0 || this.offset+equals > lineEnd {
+		err = errors.New(fmt.Sprintf("line %d: expected key = value", this.lineNumber))
+	} else {
+		key = strings.TrimSpace(this.content[this.offset : this.offset+equals])
+		if strings.HasPrefix(key, "\"") {
+			key, err = strconv.Unquote(key)
+		}
+		this.offset += equals + 1
+		this.skipSpaces()
+	}
+	return key, err
+}
+
+func (this *tomlParser) readValue() ([]string, error) {
+	var err error = nil
+	values := make([]string, 0)
+
+	if strings.HasPrefix(this.content[this.offset:], "[") {
+		// An array, which can go over many lines.
+		this.offset++
+		this.skipWhitespaceAndComments()
+		for err == nil && !strings.HasPrefix(this.content[this.offset:], "]") {
+			var value string
+			value, err = this.readString()
+			if err == nil {
+				values = append(values, value)
+				this.skipWhitespaceAndComments()
+				if strings.HasPrefix(this.content[this.offset:], ",") {
+					this.offset++
+					this.skipWhitespaceAndComments()
+				} else if !strings.HasPrefix(this.content[this.offset:], "]") {
+					err = errors.New(fmt.Sprintf("line %d: expected , or ] in array", this.lineNumber))
+				}
+			}
+		}
+		if err == nil {
+			this.offset++
+		}
+	} else if this.offset < len(this.content) && this.content[this.offset] >= '0' && this.content[this.offset] <= '9' {
+		// A number. eg: version = 1
+		lineEnd := this.findLineEnd()
+		values = append(values, strings.TrimSpace(this.stripComment(this.content[this.offset:lineEnd])))
+		this.offset = lineEnd
+	} else {
+		var value string
+		value, err = this.readString()
+		values = append(values, value)
+	}
+
+	return values, err
+}
+
+// Reads a "basic string" or a 'literal string'. Multi-line strings aren't supported.
+func (this *tomlParser) readString() (string, error) {
+	var err error = nil
+	var value string
+	rest := this.content[this.offset:]
+
+	if strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, `'''`) {
+		err = errors.New(fmt.Sprintf("line %d: multi-line strings are not supported", this.lineNumber))
+	} else if strings.HasPrefix(rest, "'") {
+		end := strings.IndexAny(rest[1:], "'\n")
+		if end < 0 || rest[1+end] != '\'' {
+			err = errors.New(fmt.Sprintf("line %d: string is not closed", this.lineNumber))
+		} else {
+			value = rest[1 : 1+end]
+			this.offset += end + 2
+		}
+	} else if strings.HasPrefix(rest, `"`) {
+		end := 1
+		for end < len(rest) && rest[end] != '"' && rest[end] != '\n' {
+			if rest[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(rest) || rest[end] != '"' {
+			err = errors.New(fmt.Sprintf("line %d: string is not closed", this.lineNumber))
+		} else {
+			value, err = strconv.Unquote(rest[:end+1])
+			this.offset += end + 1
+		}
+	} else {
+		err = errors.New(fmt.Sprintf("line %d: expected a string", this.lineNumber))
+	}
+
+	return value, err
+}
+
+// Gets the offset of the new line at the end of the current line.
+// The new line is left for skipWhitespaceAndComments, so it can count the lines.
+func (this *tomlParser) findLineEnd() int {
+	lineEnd := strings.Index(this.content[this.offset:], "\n")
+	if lineEnd < 0 {
+		return len(this.content)
+	}
+	return this.offset + lineEnd
+}
+
+// Takes any comment off the end of a line. A '#' inside a "basic string" or a 'literal string' isn't a comment.
+func (this *tomlParser) stripComment(line string) string {
+	var quote byte = 0
+	for index := 0; index < len(line); index++ {
+		c := line[index]
+		if quote == 0 {
+			if c == '#' {
+				return line[:index]
+			} else if c == '"' || c == '\'' {
+				quote = c
+			}
+		} else if c == '\\' && quote == '"' {
+			// Skip the escaped character, which could be a quote.
+			index++
+		} else if c == quote {
+			quote = 0
+		}
+	}
+	return line
+}
+
+func (this *tomlParser) skipSpaces() {
+	for this.offset < len(this.content) && (this.content[this.offset] == ' ' || this.content[this.offset] == '\t') {
+		this.offset++
+	}
+}
+
+// Skips any whitespace, new lines and comments, counting the lines as it goes.
+func (this *tomlParser) skipWhitespaceAndComments() {
+	for this.offset < len(this.content) {
+		c := this.content[this.offset]
+		if c == '#' {
+			this.offset = this.findLineEnd()
+		} else if c == '\n' {
+			this.offset++
+			this.lineNumber++
+		} else if c == ' ' || c == '\t' {
+			this.offset++
+		} else {
+			break
+		}
+	}
+}
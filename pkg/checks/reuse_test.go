@@ -0,0 +1,260 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"testing"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+	"github.com/stretchr/testify/assert"
+)
+
+const goodSidecarContent = `SPDX-FileCopyrightText: Copyright contributors to the Galasa project
+
+SPDX-License-Identifier: EPL-2.0
+`
+
+func newTestCheckError(path string) *checkTypes.CheckError {
+	checkError := checkTypes.NewCheckError(path, "Did not find copyright text.", 0)
+	checkError.IsMissingHeader = true
+	return checkError
+}
+
+func TestReuseWithoutAnyReuseFilesLeavesErrorsAlone(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	reuse, err := NewReuse(files, NewDefaultPolicy())
+	assert.Nil(t, err)
+
+	// When..
+	checkError := reuse.ResolveCheckError(newTestCheckError("images/logo.svg"))
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Equal(t, "images/logo.svg", checkError.Path)
+}
+
+func TestReuseDoesNotMakeUpForProblemsOtherThanAMissingHeader(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile("images/logo.svg.license", goodSidecarContent)
+	files.addFile(REUSE_DEP5_FILE_PATH, "Files: docs/*\nCopyright: Copyright contributors to the Galasa project\nLicense: EPL-2.0\n")
+	reuse, _ := NewReuse(files, NewDefaultPolicy())
+
+	// When..
+	sidecarCheckError := reuse.ResolveCheckError(checkTypes.NewCheckError("images/logo.svg", "Failed to read the file for checking - permission denied", 0))
+	dep5CheckError := reuse.ResolveCheckError(checkTypes.NewCheckError("docs/guide.md", "SPDX-License-Identifier 'MIT' is not allowed. Expected 'EPL-2.0'.", 0))
+
+	// Then...
+	assert.NotNil(t, sidecarCheckError)
+	assert.Contains(t, sidecarCheckError.Message, "Failed to read the file")
+	assert.NotNil(t, dep5CheckError)
+	assert.Contains(t, dep5CheckError.Message, "is not allowed")
+}
+
+func TestReuseGoodSidecarFileCoversFile(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile("images/logo.svg.license", goodSidecarContent)
+	reuse, _ := NewReuse(files, NewDefaultPolicy())
+
+	// When..
+	checkError := reuse.ResolveCheckError(newTestCheckError("images/logo.svg"))
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestReuseWrongSidecarFileIsReportedInstead(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile("images/logo.svg.license", "Copyright contributors to the Galasa project\n")
+	reuse, _ := NewReuse(files, NewDefaultPolicy())
+
+	// When..
+	checkError := reuse.ResolveCheckError(newTestCheckError("images/logo.svg"))
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Equal(t, "images/logo.svg.license", checkError.Path)
+	assert.NotNil(t, checkError.Fix)
+}
+
+func TestReuseSidecarFileForSomeoneElseIsThirdParty(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile("images/logo.svg.license", "SPDX-FileCopyrightText: 2023 Someone Else\n\nSPDX-License-Identifier: MIT\n")
+	reuse, _ := NewReuse(files, NewDefaultPolicy())
+
+	// When..
+	checkError := reuse.ResolveCheckError(newTestCheckError("images/logo.svg"))
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Equal(t, "images/logo.svg.license", checkError.Path)
+	assert.True(t, checkError.IsThirdParty)
+	assert.Nil(t, checkError.Fix)
+}
+
+func TestReuseDep5CoversMatchingFiles(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_DEP5_FILE_PATH, `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: galasa
+
+Files: images/*
+ docs/*.png
+Copyright: 2023 Contributors to the Galasa project
+License: EPL-2.0
+
+Files: third-party/*
+Copyright: 2019 Someone Else
+License: MIT
+`)
+	reuse, err := NewReuse(files, NewDefaultPolicy())
+	assert.Nil(t, err)
+
+	// When..
+	coveredError := reuse.ResolveCheckError(newTestCheckError("images/icons/logo.svg"))
+	continuedPathError := reuse.ResolveCheckError(newTestCheckError("docs/diagram.png"))
+	otherLicenseError := reuse.ResolveCheckError(newTestCheckError("third-party/lib.js"))
+	uncoveredError := reuse.ResolveCheckError(newTestCheckError("src/main.js"))
+
+	// Then...
+	assert.Nil(t, coveredError)
+	assert.Nil(t, continuedPathError)
+	assert.NotNil(t, otherLicenseError)
+	assert.NotNil(t, uncoveredError)
+}
+
+func TestReuseDep5WithoutLicenseIsInvalid(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_DEP5_FILE_PATH, "Files: images/*\nCopyright: 2023 Contributors to the Galasa project\n")
+
+	// When..
+	_, err := NewReuse(files, NewDefaultPolicy())
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Invalid REUSE file .reuse/dep5")
+}
+
+func TestReuseTomlCoversMatchingFiles(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_TOML_FILE_PATH, `version = 1
+
+# The images can't hold a header of their own.
+[[annotations]]
+path = [
+    "images/**",
+    'docs/*.png', # Only the top folder
+]
+precedence = "aggregate"
+SPDX-FileCopyrightText = ["2023 Contributors to the Galasa project"]
+SPDX-License-Identifier = "EPL-2.0"
+`)
+	reuse, err := NewReuse(files, NewDefaultPolicy())
+	assert.Nil(t, err)
+
+	// When..
+	coveredError := reuse.ResolveCheckError(newTestCheckError("images/icons/logo.svg"))
+	topFolderError := reuse.ResolveCheckError(newTestCheckError("docs/diagram.png"))
+	subFolderError := reuse.ResolveCheckError(newTestCheckError("docs/old/diagram.png"))
+
+	// Then...
+	assert.Nil(t, coveredError)
+	assert.Nil(t, topFolderError)
+	assert.NotNil(t, subFolderError)
+}
+
+func TestReuseTomlLaterAnnotationWins(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_TOML_FILE_PATH, `version = 1
+
+[[annotations]]
+path = "images/**"
+SPDX-FileCopyrightText = "Copyright contributors to the Galasa project"
+SPDX-License-Identifier = "EPL-2.0"
+
+[[annotations]]
+path = "images/vendor/**"
+SPDX-FileCopyrightText = "2019 Someone Else"
+SPDX-License-Identifier = "MIT"
+`)
+	reuse, _ := NewReuse(files, NewDefaultPolicy())
+
+	// When..
+	coveredError := reuse.ResolveCheckError(newTestCheckError("images/logo.svg"))
+	vendorError := reuse.ResolveCheckError(newTestCheckError("images/vendor/logo.svg"))
+
+	// Then...
+	assert.Nil(t, coveredError)
+	assert.NotNil(t, vendorError)
+}
+
+func TestReuseTomlWithBadSyntaxIsInvalid(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_TOML_FILE_PATH, "version = 1\n\n[[annotations]]\npath = \"images/**\n")
+
+	// When..
+	_, err := NewReuse(files, NewDefaultPolicy())
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Invalid REUSE file REUSE.toml - line 4")
+}
+
+func TestReuseTomlCommentsAreOnlyFoundOutsideStrings(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_TOML_FILE_PATH, `version = 1 # The first version
+
+[[annotations]] # The images
+path = "images/#1/**" # Not a comment: "#"
+SPDX-FileCopyrightText = 'Copyright contributors to the Galasa project' # 'quoted'
+SPDX-License-Identifier = "EPL-2.0"
+`)
+	reuse, err := NewReuse(files, NewDefaultPolicy())
+	assert.Nil(t, err)
+
+	// When..
+	checkError := reuse.ResolveCheckError(newTestCheckError("images/#1/logo.svg"))
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestReuseTomlTableNameWithHashInAStringIsNotCutShort(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_TOML_FILE_PATH, "[[\"#annotations\"]]\n")
+
+	// When..
+	_, err := NewReuse(files, NewDefaultPolicy())
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unexpected table")
+}
+
+func TestReuseSidecarTakesPriorityOverAnnotations(t *testing.T) {
+	// Given
+	files := NewFileReaderMock()
+	files.addFile(REUSE_TOML_FILE_PATH, "[[annotations]]\npath = \"**\"\nSPDX-FileCopyrightText = \"Contributors to the Galasa project\"\nSPDX-License-Identifier = \"EPL-2.0\"\n")
+	files.addFile("images/logo.svg.license", "SPDX-License-Identifier: MIT\n")
+	reuse, _ := NewReuse(files, NewDefaultPolicy())
+
+	// When..
+	checkError := reuse.ResolveCheckError(newTestCheckError("images/logo.svg"))
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Equal(t, "images/logo.svg.license", checkError.Path)
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// A part of a file which a fix changes.
+// Lines startLine to endLine of the original (counting from 1) are replaced by newLines.
+// If endLine < startLine, no lines are replaced, and newLines are inserted before startLine.
+type lineChange struct {
+	startLine int
+	endLine   int
+	newLines  []string
+}
+
+// Finds the lines which differ between the original and fixed content.
+// Fixes change the top of a file, so we find the one block of lines which differ
+// by skipping the lines which are the same at the start and at the end.
+func diffLines(originalContent string, fixedContent string) *lineChange {
+	var change *lineChange = nil
+
+	if originalContent != fixedContent {
+		originalLines := strings.Split(originalContent, "\n")
+		fixedLines := strings.Split(fixedContent, "\n")
+
+		prefixLength := 0
+		for prefixLength < len(originalLines) && prefixLength < len(fixedLines) &&
+			originalLines[prefixLength] == fixedLines[prefixLength] {
+			prefixLength++
+		}
+
+		suffixLength := 0
+		for suffixLength < len(originalLines)-prefixLength && suffixLength < len(fixedLines)-prefixLength &&
+			originalLines[len(originalLines)-1-suffixLength] == fixedLines[len(fixedLines)-1-suffixLength] {
+			suffixLength++
+		}
+
+		change = &lineChange{
+			startLine: prefixLength + 1,
+			endLine:   len(originalLines) - suffixLength,
+			newLines:  fixedLines[prefixLength : len(fixedLines)-suffixLength],
+		}
+	}
+
+	return change
+}
+
+// Turns a change into a review comment which github shows with a "Commit suggestion" button.
+// Suggestions have to replace at least one line, so an insertion is anchored on the line after it,
+// or the line before it if it is at the end of the file.
+func newSuggestionComment(path string, originalContent string, change *lineChange) *PullRequestReviewComment {
+	originalLines := strings.Split(originalContent, "\n")
+
+	startLine := change.startLine
+	endLine := change.endLine
+	newLines := change.newLines
+
+	if endLine < startLine {
+		if startLine <= len(originalLines) {
+			endLine = startLine
+			newLines = append(append([]string{}, newLines...), originalLines[startLine-1])
+		} else {
+			startLine = startLine - 1
+			endLine = startLine
+			newLines = append([]string{originalLines[startLine-1]}, newLines...)
+		}
+	}
+
+	suggestedText := strings.ReplaceAll(strings.Join(newLines, "\n"), "\r", "")
+
+	fence := "```"
+	for strings.Contains(suggestedText, fence) {
+		fence = fence + "`"
+	}
+
+	comment := &PullRequestReviewComment{
+		Path: path,
+		Line: endLine,
+		Side: "RIGHT",
+		Body: fmt.Sprintf("The copyright header of this file is not correct.\n%ssuggestion\n%s\n%s", fence, suggestedText, fence),
+	}
+	if startLine < endLine {
+		comment.StartLine = startLine
+		comment.StartSide = "RIGHT"
+	}
+
+	return comment
+}
+
+// A range of lines in the new version of a file which appear in the diff github shows for a pull request.
+type diffHunk struct {
+	firstLine int
+	lastLine  int
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`(?m)^@@ -[0-9]+(?:,[0-9]+)? \+([0-9]+)(?:,([0-9]+))? @@`)
+
+// Finds the line ranges a file's patch covers. Review comments can only go on these lines.
+func parseDiffHunks(patch string) []diffHunk {
+	hunks := make([]diffHunk, 0)
+	for _, match := range hunkHeaderPattern.FindAllStringSubmatch(patch, -1) {
+		firstLine, _ := strconv.Atoi(match[1])
+		lineCount := 1
+		if match[2] != "" {
+			lineCount, _ = strconv.Atoi(match[2])
+		}
+		if lineCount > 0 {
+			hunks = append(hunks, diffHunk{firstLine: firstLine, lastLine: firstLine + lineCount - 1})
+		}
+	}
+	return hunks
+}
+
+func isCommentWithinDiff(comment *PullRequestReviewComment, hunks []diffHunk) bool {
+	startLine := comment.StartLine
+	if startLine == 0 {
+		startLine = comment.Line
+	}
+
+	isWithin := false
+	for _, hunk := range hunks {
+		if startLine >= hunk.firstLine && comment.Line <= hunk.lastLine {
+			isWithin = true
+			break
+		}
+	}
+	return isWithin
+}
+
+// Builds the review comments which suggest fixes for the check errors.
+// Github rejects a review with comments on lines which aren't part of the pull request diff,
+// so fixes for lines the pull request didn't touch are left out.
+func buildSuggestionComments(checkErrors []checkTypes.CheckError, filesChanged []File) []PullRequestReviewComment {
+	patchesByPath := make(map[string]string)
+	for _, file := range filesChanged {
+		patchesByPath[file.Filename] = file.Patch
+	}
+
+	comments := make([]PullRequestReviewComment, 0)
+	for _, checkError := range checkErrors {
+		if checkError.Fix != nil {
+			change := diffLines(checkError.Fix.OriginalContent, checkError.Fix.FixedContent)
+			if change != nil {
+				comment := newSuggestionComment(checkError.Path, checkError.Fix.OriginalContent, change)
+				if isCommentWithinDiff(comment, parseDiffHunks(patchesByPath[checkError.Path])) {
+					comments = append(comments, *comment)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Path < comments[j].Path
+	})
+
+	return comments
+}
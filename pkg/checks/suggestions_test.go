@@ -0,0 +1,159 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+	"github.com/stretchr/testify/assert"
+)
+
+const suggestedJavaHeader = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */`
+
+func TestDiffOfSameContentIsNil(t *testing.T) {
+	change := diffLines("a\nb\n", "a\nb\n")
+	assert.Nil(t, change)
+}
+
+func TestDiffOfReplacedLinesFindsThem(t *testing.T) {
+	change := diffLines("a\nold1\nold2\nd\n", "a\nnew1\nd\n")
+	assert.Equal(t, 2, change.startLine)
+	assert.Equal(t, 3, change.endLine)
+	assert.Equal(t, []string{"new1"}, change.newLines)
+}
+
+func TestDiffOfInsertedLinesHasNoReplacedLines(t *testing.T) {
+	change := diffLines("package x;\n", "header\npackage x;\n")
+	assert.Equal(t, 1, change.startLine)
+	assert.Equal(t, 0, change.endLine)
+	assert.Equal(t, []string{"header"}, change.newLines)
+}
+
+func TestSuggestionForInsertionIsAnchoredOnNextLine(t *testing.T) {
+	original := "package x;\n"
+	change := diffLines(original, "header\npackage x;\n")
+
+	comment := newSuggestionComment("X.java", original, change)
+
+	assert.Equal(t, 1, comment.Line)
+	assert.Equal(t, 0, comment.StartLine)
+	assert.Contains(t, comment.Body, "```suggestion\nheader\npackage x;\n```")
+}
+
+func TestSuggestionForReplacementCoversReplacedLines(t *testing.T) {
+	original := "/*\n * Copyright IBM Corp. 2021\n * All rights reserved\n */\npackage x;\n"
+	change := diffLines(original, suggestedJavaHeader+"\npackage x;\n")
+
+	comment := newSuggestionComment("X.java", original, change)
+
+	assert.Equal(t, 2, comment.StartLine)
+	assert.Equal(t, "RIGHT", comment.StartSide)
+	assert.Equal(t, 3, comment.Line)
+	assert.Contains(t, comment.Body, "```suggestion\n * Copyright contributors to the Galasa project\n *\n * SPDX-License-Identifier: EPL-2.0\n```")
+}
+
+func TestParseDiffHunksFindsNewFileLineRanges(t *testing.T) {
+	patch := "@@ -0,0 +1,3 @@\n+a\n+b\n+c\n@@ -10,2 +12 @@\n-x\n y\n@@ -20,1 +21,0 @@\n-z\n"
+	hunks := parseDiffHunks(patch)
+	assert.Equal(t, []diffHunk{{firstLine: 1, lastLine: 3}, {firstLine: 12, lastLine: 12}}, hunks)
+}
+
+func TestSuggestionsAreOnlyMadeForLinesInTheDiff(t *testing.T) {
+	// Given
+	newFileContent := "package x;\n"
+	oldFileContent := "package y;\nline 2\nline 3\nline 4\n"
+	checkErrors := []checkTypes.CheckError{
+		{Path: "New.java", Fix: &checkTypes.FileFix{OriginalContent: newFileContent, FixedContent: suggestedJavaHeader + "\n" + newFileContent}},
+		{Path: "Old.java", Fix: &checkTypes.FileFix{OriginalContent: oldFileContent, FixedContent: suggestedJavaHeader + "\n" + oldFileContent}},
+		{Path: "Unfixable.java"},
+	}
+	filesChanged := []File{
+		{Filename: "New.java", Patch: "@@ -0,0 +1,1 @@\n+package x;\n"},
+		{Filename: "Old.java", Patch: "@@ -3,2 +3,2 @@\n line 3\n-line four\n+line 4\n"},
+	}
+
+	// When..
+	comments := buildSuggestionComments(checkErrors, filesChanged)
+
+	// Then...
+	assert.Equal(t, 1, len(comments))
+	assert.Equal(t, "New.java", comments[0].Path)
+	assert.Equal(t, 1, comments[0].Line)
+}
+
+// Serves the reviews of a pull request, counting the reviews which are posted.
+func newTestReviewsServer(existingReviews string, postedReviews *[]PullRequestReview) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.WriteHeader(http.StatusOK)
+			if r.URL.Query().Get("page") == "1" {
+				w.Write([]byte(existingReviews))
+			} else {
+				w.Write([]byte("[]"))
+			}
+		} else {
+			var review PullRequestReview
+			json.NewDecoder(r.Body).Decode(&review)
+			*postedReviews = append(*postedReviews, review)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("{}"))
+		}
+	}))
+}
+
+func newTestSuggestions() ([]checkTypes.CheckError, []File) {
+	content := "package x;\n"
+	checkErrors := []checkTypes.CheckError{
+		{Path: "New.java", Fix: &checkTypes.FileFix{OriginalContent: content, FixedContent: suggestedJavaHeader + "\n" + content}},
+	}
+	filesChanged := []File{{Filename: "New.java", Patch: "@@ -0,0 +1,1 @@\n+package x;\n"}}
+	return checkErrors, filesChanged
+}
+
+func TestSuggestFixesReviewsPullRequestWithMarker(t *testing.T) {
+	// Given
+	postedReviews := make([]PullRequestReview, 0)
+	server := newTestReviewsServer(`[{"commit_id":"old-sha","body":"`+SUGGESTIONS_REVIEW_MARKER+`"}]`, &postedReviews)
+	defer server.Close()
+
+	handler := &EventHandlerImpl{gitHubClient: NewGitHubClient(server.URL, server.URL, false)}
+	pullRequest := &WebhookPullRequest{Number: 7, Head: WebhookPullRequestHead{Sha: "new-sha"}}
+	checkErrors, filesChanged := newTestSuggestions()
+
+	// When..
+	handler.suggestFixes("token", &Webhook{}, 1, pullRequest, checkErrors, filesChanged)
+
+	// Then...
+	assert.Equal(t, 1, len(postedReviews))
+	assert.Equal(t, "new-sha", postedReviews[0].CommitId)
+	assert.True(t, strings.HasPrefix(postedReviews[0].Body, SUGGESTIONS_REVIEW_MARKER))
+}
+
+func TestSuggestFixesDoesNotReviewTheSameHeadCommitTwice(t *testing.T) {
+	// Given
+	postedReviews := make([]PullRequestReview, 0)
+	server := newTestReviewsServer(`[{"commit_id":"new-sha","body":"Looks good"},{"commit_id":"new-sha","body":"`+SUGGESTIONS_REVIEW_MARKER+`\nSuggested fixes"}]`, &postedReviews)
+	defer server.Close()
+
+	handler := &EventHandlerImpl{gitHubClient: NewGitHubClient(server.URL, server.URL, false)}
+	pullRequest := &WebhookPullRequest{Number: 7, Head: WebhookPullRequestHead{Sha: "new-sha"}}
+	checkErrors, filesChanged := newTestSuggestions()
+
+	// When..
+	handler.suggestFixes("token", &Webhook{}, 1, pullRequest, checkErrors, filesChanged)
+
+	// Then...
+	assert.Empty(t, postedReviews)
+}
@@ -7,7 +7,6 @@ package checks
 
 import (
 	"crypto/rsa"
-	"fmt"
 	"log"
 	"os"
 	"time"
@@ -32,15 +31,19 @@ type TokenSupplierImpl struct {
 	tokens map[int]githubToken
 
 	gitHubClient GitHubClient
+
+	// The id of the github app which the JWT tokens are issued for.
+	appId int
 }
 
-func NewTokenSupplier(gitHubClient GitHubClient, keyFilePath string) (TokenSupplier, error) {
+func NewTokenSupplier(gitHubClient GitHubClient, keyFilePath string, appId int) (TokenSupplier, error) {
 
 	var err error = nil
 	this := new(TokenSupplierImpl)
 	this.gitHubClient = gitHubClient
+	this.appId = appId
 
-	log.Printf("Using key file %s", keyFilePath)
+	log.Printf("Using key file %s for github app %v", keyFilePath, appId)
 
 	var keyBytes []byte
 	keyBytes, err = os.ReadFile(keyFilePath)
@@ -74,7 +77,7 @@ func (this *TokenSupplierImpl) GetToken(installation int) (string, error) {
 	this.reissue = time.Now().Add(time.Minute * 8)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
-		"iss": 125351,
+		"iss": this.appId,
 		"iat": iat.Unix(),
 		"exp": exp.Unix(),
 	})
@@ -84,10 +87,8 @@ func (this *TokenSupplierImpl) GetToken(installation int) (string, error) {
 	if err == nil {
 
 		// We have a valid github jwt token,  now to get the installation token
-		accessUrl := fmt.Sprintf("https://api.github.com/app/installations/%v/access_tokens", installation)
-
 		var tokenResponse InstallationToken
-		tokenResponse, err = this.gitHubClient.GetNewToken(accessUrl, tokenString)
+		tokenResponse, err = this.gitHubClient.GetNewToken(installation, tokenString)
 
 		if err == nil {
 			var expiresAt time.Time
@@ -0,0 +1,41 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	WEBHOOK_SIGNATURE_HEADER = "X-Hub-Signature-256"
+	WEBHOOK_SIGNATURE_PREFIX = "sha256="
+)
+
+// Checks the value of the X-Hub-Signature-256 header which github sends with every
+// webhook. The header holds "sha256=" followed by the hex HMAC-SHA256 of the raw payload,
+// keyed using the webhook secret configured for the github app.
+func isWebhookSignatureValid(payload []byte, signatureHeader string, secret []byte) bool {
+	isValid := false
+
+	if len(secret) > 0 && strings.HasPrefix(signatureHeader, WEBHOOK_SIGNATURE_PREFIX) {
+		var signature []byte
+		var err error
+		signature, err = hex.DecodeString(strings.TrimPrefix(signatureHeader, WEBHOOK_SIGNATURE_PREFIX))
+		if err == nil {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(payload)
+			expectedSignature := mac.Sum(nil)
+
+			// Constant-time comparison, so we don't leak how much of the signature matched.
+			isValid = hmac.Equal(signature, expectedSignature)
+		}
+	}
+
+	return isValid
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Example taken from the github documentation on validating webhook deliveries.
+const (
+	testWebhookSecret    = "It's a Secret to Everybody"
+	testWebhookPayload   = "Hello, World!"
+	testWebhookSignature = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+)
+
+func TestValidSignatureIsAccepted(t *testing.T) {
+	isValid := isWebhookSignatureValid([]byte(testWebhookPayload), testWebhookSignature, []byte(testWebhookSecret))
+	assert.True(t, isValid)
+}
+
+func TestSignatureOfDifferentPayloadIsRejected(t *testing.T) {
+	isValid := isWebhookSignatureValid([]byte("Goodbye, World!"), testWebhookSignature, []byte(testWebhookSecret))
+	assert.False(t, isValid)
+}
+
+func TestSignatureUsingDifferentSecretIsRejected(t *testing.T) {
+	isValid := isWebhookSignatureValid([]byte(testWebhookPayload), testWebhookSignature, []byte("not the secret"))
+	assert.False(t, isValid)
+}
+
+func TestMissingSignatureIsRejected(t *testing.T) {
+	isValid := isWebhookSignatureValid([]byte(testWebhookPayload), "", []byte(testWebhookSecret))
+	assert.False(t, isValid)
+}
+
+func TestSignatureWithoutPrefixIsRejected(t *testing.T) {
+	isValid := isWebhookSignatureValid([]byte(testWebhookPayload), "757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", []byte(testWebhookSecret))
+	assert.False(t, isValid)
+}
+
+func TestSignatureWhichIsNotHexIsRejected(t *testing.T) {
+	isValid := isWebhookSignatureValid([]byte(testWebhookPayload), "sha256=not-hex-at-all", []byte(testWebhookSecret))
+	assert.False(t, isValid)
+}
+
+func TestEmptySecretRejectsEverything(t *testing.T) {
+	isValid := isWebhookSignatureValid([]byte(testWebhookPayload), testWebhookSignature, []byte{})
+	assert.False(t, isValid)
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// How a comment line starts in a batch file. eg: "REM", "@rem" or "::"
+const BATCH_COMMENT_REGEX = `(?i:@?rem\b|::)`
+
+// Checks Windows batch files, which have a block of REM or :: comment lines at the top.
+// The header can come after an @echo off line, so the comment lines aren't shown as the script runs.
+type BatchFileChecker struct {
+	batchCopyrightPattern         *headerPattern
+	batchExpectedCopyrightHeader  string
+	batchExpectedCopyrightMessage string
+
+	commentLinePattern *regexp.Regexp
+	echoOffLinePattern *regexp.Regexp
+}
+
+func NewBatchFileChecker() FileChecker {
+	return NewBatchFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewBatchFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := new(BatchFileChecker)
+
+	this.batchCopyrightPattern = header.buildCopyrightPatternForCommentRegex(BATCH_COMMENT_REGEX)
+	this.batchExpectedCopyrightHeader = header.buildExpectedHeader("REM", "REM", "REM")
+	this.batchExpectedCopyrightMessage = buildExpectedMessage(this.batchExpectedCopyrightHeader)
+
+	this.commentLinePattern = regexp.MustCompile(`^` + BATCH_COMMENT_REGEX)
+	this.echoOffLinePattern = regexp.MustCompile(`^(?i:@echo\s+off)\s*$`)
+
+	return this
+}
+
+func (this *BatchFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *BatchFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
+
+	var checkError *checkTypes.CheckError = nil
+
+	blockStart := this.skipEchoOffLine(content)
+	blockEnd := this.findEndOfCommentLines(content, blockStart)
+
+	if blockEnd == blockStart {
+		checkError = &checkTypes.CheckError{
+			Path:            fileName,
+			Message:         "A comment block is missing at the start of the file." + this.batchExpectedCopyrightMessage,
+			Location:        0,
+			IsMissingHeader: true,
+		}
+	} else {
+		checkError = checkCommentBlock(content, blockStart, blockEnd, fileName, this.batchCopyrightPattern, this.batchExpectedCopyrightMessage)
+	}
+
+	return checkError
+}
+
+func (this *BatchFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		// Keep the @echo off line first.
+		restStart := this.skipEchoOffLine(content)
+		echoOffLine := strings.TrimRight(content[:restStart], " \t\r\n")
+		rest := content[restStart:]
+
+		// Take out any existing header, as it is wrong.
+		blockEnd := this.findEndOfCommentLines(rest, 0)
+		if blockEnd > 0 && isHeaderLikeComment(rest[:blockEnd]) {
+			rest = rest[blockEnd:]
+		}
+
+		fixedContent = prependHeader(this.batchExpectedCopyrightHeader, rest)
+		if echoOffLine != "" {
+			fixedContent = echoOffLine + "\n" + fixedContent
+		}
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Gets the offset of the first line which isn't blank after the @echo off line, or at the start if there isn't one.
+func (this *BatchFileChecker) skipEchoOffLine(content string) int {
+	offset := skipBlankLines(content, 0)
+	nextLine := getNextLineOffset(content, offset)
+	if this.echoOffLinePattern.MatchString(strings.TrimRight(content[offset:nextLine], "\r\n")) {
+		offset = skipBlankLines(content, nextLine)
+	}
+	return offset
+}
+
+// Starting at a line which begins at the offset, finds the end of the run of REM or :: comment lines.
+// Returns the starting offset if the first line isn't a comment.
+func (this *BatchFileChecker) findEndOfCommentLines(content string, offset int) int {
+	for offset < len(content) {
+		nextLine := getNextLineOffset(content, offset)
+		if !this.commentLinePattern.MatchString(content[offset:nextLine]) {
+			break
+		}
+		offset = nextLine
+	}
+	return offset
+}
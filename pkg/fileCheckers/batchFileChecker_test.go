@@ -0,0 +1,122 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedBatchHeader = `REM
+REM Copyright contributors to the Galasa project
+REM
+REM SPDX-License-Identifier: EPL-2.0
+REM`
+
+func TestCheckBatchContent(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		expectedMessage string
+	}{
+		{
+			name: "REM copyright ok",
+			content: `REM
+REM Copyright contributors to the Galasa project
+REM
+REM SPDX-License-Identifier: EPL-2.0
+REM
+echo hello
+`,
+		},
+		{
+			name: "lower case rem copyright ok with windows line endings",
+			content: "rem Copyright contributors to the Galasa project\r\n" +
+				"rem\r\n" +
+				"rem SPDX-License-Identifier: EPL-2.0\r\n" +
+				"echo hello\r\n",
+		},
+		{
+			name: ":: copyright ok after @echo off",
+			content: `@echo off
+
+:: Copyright contributors to the Galasa project
+::
+:: SPDX-License-Identifier: EPL-2.0
+echo hello
+`,
+		},
+		{
+			name: "@REM copyright ok after @ECHO OFF",
+			content: `@ECHO OFF
+@REM Copyright contributors to the Galasa project
+@REM
+@REM SPDX-License-Identifier: EPL-2.0
+`,
+		},
+		{
+			name: "no comment",
+			content: `@echo off
+echo hello
+`,
+			expectedMessage: "A comment block is missing at the start of the file",
+		},
+		{
+			name: "REMARK is not a comment",
+			content: `REMARK Copyright contributors to the Galasa project
+`,
+			expectedMessage: "A comment block is missing at the start of the file",
+		},
+		{
+			name: "comment without copyright",
+			content: `REM Installs Galasa
+echo hello
+`,
+			expectedMessage: "Did not find copyright text in first comment block",
+		},
+		{
+			name: "too many copyrights",
+			content: `REM Copyright contributors to the Galasa project
+REM SPDX-License-Identifier: EPL-2.0
+:: Copyright contributors to the Galasa project
+:: SPDX-License-Identifier: EPL-2.0
+`,
+			expectedMessage: "Found too many copyright texts in first comment block",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Given
+			checker := NewBatchFileChecker()
+
+			// When..
+			checkError := checker.CheckFileContent(test.content, "install.bat")
+
+			// Then...
+			if test.expectedMessage == "" {
+				assert.Nil(t, checkError)
+			} else {
+				assert.NotNil(t, checkError)
+				assert.Contains(t, checkError.Message, test.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestFixBatchContentKeepsEchoOffFirst(t *testing.T) {
+	// Given
+	checker := NewBatchFileChecker().(FileFixer)
+	var content = "@echo off\nREM Copyright IBM Corp. 2021\necho hello\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "install.cmd")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "@echo off\n"+expectedBatchHeader+"\necho hello\n", fixedContent)
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// Checks C and C++ files, which have either a /* ... */ comment block or a run of // comment lines at the top.
+// Only whitespace can come before the header, so it is above any include guard.
+//
+// The same checker is used for other languages with C-style comments, some of which
+// need particular lines to come before the header. eg: a //go:build line in go.
+type CFileChecker struct {
+	blockCopyrightPattern    *headerPattern
+	lineCopyrightPattern     *headerPattern
+	expectedCopyrightHeader  string
+	expectedCopyrightMessage string
+
+	// Lines which are allowed above the header.
+	leadingLinePatterns []*regexp.Regexp
+}
+
+// A marker tool-generated files have, which has to stay at the top. See https://go.dev/s/generatedcode
+// The file classifier looks for it too, so it is shared.
+var GeneratedCodeLinePattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.\s*$`)
+
+func NewCFileChecker() FileChecker {
+	return NewCFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewCFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	return newCFileCheckerWithLeadingLines(header)
+}
+
+// Checks go files, which can have build constraints and a generated code marker above the header.
+func NewGoFileChecker() FileChecker {
+	return NewGoFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewGoFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	return newCFileCheckerWithLeadingLines(header,
+		regexp.MustCompile(`^//go:build\s`),
+		regexp.MustCompile(`^// \+build\s`),
+		GeneratedCodeLinePattern,
+	)
+}
+
+// Checks javascript and typescript files, which can have a #! line and a generated code marker above the header.
+func NewJavascriptFileChecker() FileChecker {
+	return NewJavascriptFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewJavascriptFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	return newCFileCheckerWithLeadingLines(header,
+		regexp.MustCompile(`^#!`),
+		GeneratedCodeLinePattern,
+	)
+}
+
+func newCFileCheckerWithLeadingLines(header CopyrightHeader, leadingLinePatterns ...*regexp.Regexp) *CFileChecker {
+	this := new(CFileChecker)
+
+	this.leadingLinePatterns = leadingLinePatterns
+
+	this.blockCopyrightPattern = header.buildCopyrightPattern("*")
+	this.lineCopyrightPattern = header.buildCopyrightPattern("//")
+
+	// Either style is accepted, but we suggest the same header as for java.
+	this.expectedCopyrightHeader = header.buildExpectedHeader("/*", " *", " */")
+	this.expectedCopyrightMessage = buildExpectedMessage(this.expectedCopyrightHeader)
+
+	return this
+}
+
+func (this *CFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *CFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
+
+	var checkError *checkTypes.CheckError = nil
+
+	blockStart := this.skipLeadingLines(content)
+	blockEnd, copyrightPattern := this.findHeaderComment(content, blockStart)
+
+	if blockEnd == blockStart {
+		checkError = &checkTypes.CheckError{
+			Path:            fileName,
+			Message:         "A comment block is missing at the start of the file." + this.expectedCopyrightMessage,
+			Location:        0,
+			IsMissingHeader: true,
+		}
+	} else {
+		checkError = checkCommentBlock(content, blockStart, blockEnd, fileName, copyrightPattern, this.expectedCopyrightMessage)
+	}
+
+	return checkError
+}
+
+func (this *CFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		// Lines such as //go:build have to stay first, with a blank line after them.
+		restStart := this.skipLeadingLines(content)
+		leadingLines := strings.TrimRight(content[:restStart], " \t\r\n")
+		rest := content[restStart:]
+
+		// Take out any existing header, as it is wrong.
+		blockEnd, _ := this.findHeaderComment(rest, 0)
+		if blockEnd > 0 && isHeaderLikeComment(rest[:blockEnd]) {
+			// Keep the new line at the end of a run of // lines, so any blank line after them stays.
+			rest = rest[len(strings.TrimRight(rest[:blockEnd], "\r\n")):]
+		}
+
+		fixedContent = prependHeader(this.expectedCopyrightHeader, rest)
+		if leadingLines != "" {
+			fixedContent = leadingLines + "\n\n" + fixedContent
+		}
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Gets the offset of the first non-whitespace character which isn't on one of the lines allowed above the header.
+func (this *CFileChecker) skipLeadingLines(content string) int {
+	offset := skipWhitespace(content, 0)
+
+	isLeadingLine := true
+	for isLeadingLine && offset < len(content) {
+		nextLine := getNextLineOffset(content, offset)
+		isLeadingLine = false
+		for _, pattern := range this.leadingLinePatterns {
+			if pattern.MatchString(strings.TrimRight(content[offset:nextLine], "\r\n")) {
+				isLeadingLine = true
+			}
+		}
+		if isLeadingLine {
+			offset = skipWhitespace(content, nextLine)
+		}
+	}
+
+	return offset
+}
+
+// Finds the end of the /* ... */ comment block or run of // comment lines which starts at the offset,
+// and the pattern which finds the copyright inside that style of comment.
+// If there is no comment there, or it is never closed, the offset is returned unchanged.
+func (this *CFileChecker) findHeaderComment(content string, offset int) (int, *headerPattern) {
+	blockEnd := offset
+	copyrightPattern := this.blockCopyrightPattern
+
+	if strings.HasPrefix(content[offset:], "/*") {
+		closeIndex := strings.Index(content[offset+len("/*"):], "*/")
+		if closeIndex >= 0 {
+			blockEnd = offset + len("/*") + closeIndex + len("*/")
+		}
+	} else if strings.HasPrefix(content[offset:], "//") {
+		blockEnd = findEndOfLinesWithPrefix(content, offset, "//")
+		copyrightPattern = this.lineCopyrightPattern
+	}
+
+	return blockEnd, copyrightPattern
+}
+
+// Gets the offset of the first non-whitespace character at or after the offset.
+func skipWhitespace(content string, offset int) int {
+	for offset < len(content) && unicode.IsSpace(rune(content[offset])) {
+		offset++
+	}
+	return offset
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCContentFindsBlockCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+#include <stdio.h>
+`
+	var fileName = "test.c"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckCContentFindsLineCommentCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `// Copyright contributors to the Galasa project
+//
+// SPDX-License-Identifier: EPL-2.0
+
+#include <iostream>
+`
+	var fileName = "test.cpp"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckCHeaderFindsCopyrightOkAboveIncludeGuard(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+#ifndef BRIDGE_H
+#define BRIDGE_H
+/* Nothing to see here */
+#endif
+`
+	var fileName = "bridge.h"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckCHeaderWithCopyrightBelowIncludeGuardFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `#ifndef BRIDGE_H
+#define BRIDGE_H
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+#endif
+`
+	var fileName = "bridge.h"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckCContentFindsCopyrightMissing(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `// The bridge to z/OS
+// which does things.
+int main() {}
+`
+	var fileName = "test.cc"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in first comment block")
+	assert.Equal(t, 1, checkError.StartLine)
+	assert.Equal(t, 2, checkError.EndLine)
+}
+
+func TestCheckCContentWithUnclosedCommentFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+`
+	var fileName = "test.c"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckCContentFindsTooManyCopyright(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `// Copyright contributors to the Galasa project
+//
+// SPDX-License-Identifier: EPL-2.0
+// Copyright contributors to the Galasa project
+//
+// SPDX-License-Identifier: EPL-2.0
+`
+	var fileName = "test.hpp"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Found too many copyright texts in first comment block")
+}
+
+func TestFixCContentReplacesLineCommentHeader(t *testing.T) {
+	// Given
+	checker := NewCFileChecker().(FileFixer)
+	var content = `// Copyright IBM Corp. 2021
+#include <stdio.h>
+`
+	var fileName = "test.c"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJavaHeader+"\n#include <stdio.h>\n", fixedContent)
+}
+
+func TestCheckGoContentFindsLineCommentCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewGoFileChecker()
+	var content = `// Copyright contributors to the Galasa project
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package main
+`
+	var fileName = "main.go"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckGoContentFindsCopyrightOkAfterBuildConstraints(t *testing.T) {
+	// Given
+	checker := NewGoFileChecker()
+	var content = `//go:build linux && amd64
+// +build linux,amd64
+
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+`
+	var fileName = "main_linux.go"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckGoContentFindsCopyrightOkAfterGeneratedCodeMarker(t *testing.T) {
+	// Given
+	checker := NewGoFileChecker()
+	var content = `// Code generated by mockgen. DO NOT EDIT.
+// Copyright contributors to the Galasa project
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package mocks
+`
+	var fileName = "mocks.go"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckGoContentWithOnlyBuildConstraintFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewGoFileChecker()
+	var content = `//go:build linux
+
+package main
+`
+	var fileName = "main.go"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckCContentDoesNotAllowGoBuildConstraintFirst(t *testing.T) {
+	// Given
+	checker := NewCFileChecker()
+	var content = `//go:build linux
+
+// Copyright contributors to the Galasa project
+//
+// SPDX-License-Identifier: EPL-2.0
+`
+	var fileName = "test.c"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in first comment block")
+}
+
+func TestCheckJavascriptContentFindsCopyrightOkAfterShebang(t *testing.T) {
+	// Given
+	checker := NewJavascriptFileChecker()
+	var content = `#!/usr/bin/env node
+// Copyright contributors to the Galasa project
+//
+// SPDX-License-Identifier: EPL-2.0
+console.log("hello");
+`
+	var fileName = "cli.js"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestFixGoContentKeepsBuildConstraintFirst(t *testing.T) {
+	// Given
+	checker := NewGoFileChecker().(FileFixer)
+	var content = `//go:build linux
+// Copyright IBM Corp. 2021
+
+package main
+`
+	var fileName = "main.go"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "//go:build linux\n\n"+expectedJavaHeader+"\n\npackage main\n", fixedContent)
+}
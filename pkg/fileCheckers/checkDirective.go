@@ -0,0 +1,122 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+	"github.com/galasa-dev/githubapp-copyright/pkg/spdx"
+)
+
+// How many lines at the top of a file we look in for a copyright-check directive,
+// and for the licence it allows.
+const CHECK_DIRECTIVE_LINE_COUNT = 20
+
+// A comment in a file which changes how it is checked. eg:
+//
+//	// copyright-check: ignore
+//	# copyright-check: allow-license Apache-2.0
+//
+// The directive has to be the whole of a comment line, so one which is just mentioned in a string or in some prose doesn't count.
+var checkDirectivePattern = regexp.MustCompile(`(?m)^[ \t]*(//|#|--|;|\*|/\*|<!--|(?i:rem)|::)[ \t]*` +
+	`(copyright-check:[ \t]*(ignore|allow-license[ \t]+([A-Za-z0-9][A-Za-z0-9.+-]*)))` +
+	`[ \t]*(\*/|-->)?[ \t]*\r?$`)
+
+// A copyright-check directive found in a file. Only the first one in a file counts.
+type CheckDirective struct {
+	// The directive as it is written in the file. eg: "copyright-check: allow-license Apache-2.0"
+	Text string
+
+	// The file isn't checked at all.
+	IsIgnore bool
+
+	// The file passes if it has this SPDX licence instead of the expected header. eg: "Apache-2.0"
+	// Some files legitimately carry the header of the project they came from.
+	AllowedLicenseId string
+
+	// Where the directive is in the content.
+	start int
+	end   int
+
+	// Finds the SPDX-License-Identifier line for the allowed licence. nil if the allowed licence isn't a known one.
+	allowedLicensePattern *regexp.Regexp
+
+	// Why the allowed licence isn't a known one, or nil if it is.
+	allowedLicenseErr error
+}
+
+// Finds the copyright-check directive at the top of a file. Returns nil if there isn't one.
+// The file checkers can be given the directive, so it is only found once for each file.
+func FindCheckDirective(content string) *CheckDirective {
+	var directive *CheckDirective = nil
+
+	match := checkDirectivePattern.FindStringSubmatchIndex(getTopLines(content, CHECK_DIRECTIVE_LINE_COUNT))
+	if match != nil {
+		directive = new(CheckDirective)
+		directive.start = match[4]
+		directive.end = match[5]
+		directive.Text = content[match[4]:match[5]]
+		directive.IsIgnore = (content[match[6]:match[7]] == "ignore")
+		if match[8] >= 0 {
+			directive.AllowedLicenseId = content[match[8]:match[9]]
+			_, directive.allowedLicenseErr = spdx.ParseExpression(directive.AllowedLicenseId)
+			if directive.allowedLicenseErr == nil {
+				directive.allowedLicensePattern = regexp.MustCompile(`SPDX-License-Identifier:\s*` + regexp.QuoteMeta(directive.AllowedLicenseId) + `([^A-Za-z0-9.+-]|$)`)
+			}
+		}
+	}
+
+	return directive
+}
+
+// Does the directive mean the file passes, whatever its copyright header ?
+// An allow-license directive only does if the file has an SPDX-License-Identifier for the licence it allows.
+func (this *CheckDirective) IsSuppressing(content string) bool {
+	isSuppressing := false
+	if this != nil {
+		if this.IsIgnore {
+			isSuppressing = true
+		} else if this.allowedLicensePattern != nil {
+			isSuppressing = this.allowedLicensePattern.MatchString(getTopLines(content, CHECK_DIRECTIVE_LINE_COUNT))
+		}
+	}
+	return isSuppressing
+}
+
+// Gets the problem with the directive itself, or nil if there isn't one.
+// An allow-license directive for a licence which isn't a known SPDX one would never allow anything, so it is reported.
+func (this *CheckDirective) NewCheckError(content string, fileName string) *checkTypes.CheckError {
+	var checkError *checkTypes.CheckError = nil
+	if this != nil && this.allowedLicenseErr != nil {
+		checkError = checkTypes.NewCheckErrorForRange(
+			fileName,
+			fmt.Sprintf("The copyright-check directive allows licence '%s', which is not valid - %s.", this.AllowedLicenseId, this.allowedLicenseErr.Error()),
+			content,
+			this.start,
+			this.end,
+		)
+	}
+	return checkError
+}
+
+// Does the directive decide the result of checking the file, so the file checker doesn't need to look at its header ?
+// If so, the problem with the directive is returned too, or nil if the file passes.
+func (this *CheckDirective) decidesCheck(content string, fileName string) (bool, *checkTypes.CheckError) {
+	checkError := this.NewCheckError(content, fileName)
+	return checkError != nil || this.IsSuppressing(content), checkError
+}
+
+// Gets the first few lines of the content.
+func getTopLines(content string, lineCount int) string {
+	lines := strings.SplitN(content, "\n", lineCount+1)
+	if len(lines) > lineCount {
+		lines = lines[:lineCount]
+	}
+	return strings.Join(lines, "\n")
+}
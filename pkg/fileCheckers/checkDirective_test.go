@@ -0,0 +1,198 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileWithoutDirectiveHasNone(t *testing.T) {
+	// Given
+	content := "package dev.galasa;\n"
+
+	// When..
+	directive := FindCheckDirective(content)
+
+	// Then...
+	assert.Nil(t, directive)
+	assert.False(t, directive.IsSuppressing(content))
+}
+
+func TestIgnoreDirectiveSuppressesChecks(t *testing.T) {
+	// Given
+	content := "// copyright-check: ignore\npackage dev.galasa;\n"
+
+	// When..
+	checkError := NewJavaFileChecker().CheckFileContent(content, "Test.java")
+	directive := FindCheckDirective(content)
+
+	// Then...
+	assert.Nil(t, checkError)
+	assert.True(t, directive.IsIgnore)
+	assert.Equal(t, "copyright-check: ignore", directive.Text)
+}
+
+func TestIgnoreDirectiveIsHonouredByOtherCheckers(t *testing.T) {
+	// Given
+	content := "# copyright-check: ignore\nkey: value\n"
+
+	// When..
+	yamlCheckError := NewYamlFileChecker().CheckFileContent(content, "test.yaml")
+	pythonCheckError := NewPythonFileChecker().CheckFileContent(content, "test.py")
+	xmlCheckError := NewXmlFileChecker().CheckFileContent("<!-- copyright-check: ignore -->\n<root/>\n", "test.xml")
+
+	// Then...
+	assert.Nil(t, yamlCheckError)
+	assert.Nil(t, pythonCheckError)
+	assert.Nil(t, xmlCheckError)
+}
+
+func TestIgnoreDirectiveInStringIsNotHonoured(t *testing.T) {
+	// Given
+	content := "package dev.galasa;\nString directive = \"copyright-check: ignore\";\n"
+
+	// When..
+	checkError := NewJavaFileChecker().CheckFileContent(content, "Test.java")
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Nil(t, FindCheckDirective(content))
+}
+
+func TestIgnoreDirectiveInProseCommentIsNotHonoured(t *testing.T) {
+	// Given
+	content := "# Files can add copyright-check: ignore to opt out.\nkey: value\n"
+
+	// When..
+	checkError := NewYamlFileChecker().CheckFileContent(content, "test.yaml")
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Nil(t, FindCheckDirective(content))
+}
+
+func TestIgnoreDirectiveIsHonouredInOtherCommentStyles(t *testing.T) {
+	// Given
+	contents := []string{
+		"/* copyright-check: ignore */\n",
+		"/*\n * copyright-check: ignore\n */\n",
+		"-- copyright-check: ignore\n",
+		"REM copyright-check: ignore\r\n",
+		":: copyright-check: ignore\n",
+	}
+
+	for _, content := range contents {
+		// When..
+		directive := FindCheckDirective(content)
+
+		// Then...
+		assert.NotNil(t, directive, content)
+		assert.Equal(t, "copyright-check: ignore", directive.Text)
+	}
+}
+
+func TestIgnoreDirectiveAfterTopLinesIsNotHonoured(t *testing.T) {
+	// Given
+	content := strings.Repeat("key: value\n", CHECK_DIRECTIVE_LINE_COUNT) + "# copyright-check: ignore\n"
+
+	// When..
+	checkError := NewYamlFileChecker().CheckFileContent(content, "test.yaml")
+
+	// Then...
+	assert.NotNil(t, checkError)
+}
+
+func TestAllowLicenseDirectiveAcceptsThatLicense(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+
+	// When..
+	checkError := checker.CheckFileContent(apacheJavaContent, "Other.java")
+	directive := FindCheckDirective(apacheJavaContent)
+
+	// Then...
+	assert.Nil(t, checkError)
+	assert.Equal(t, "Apache-2.0", directive.AllowedLicenseId)
+	assert.Equal(t, "copyright-check: allow-license Apache-2.0", directive.Text)
+}
+
+func TestAllowLicenseDirectiveDoesNotAcceptOtherLicenses(t *testing.T) {
+	// Given
+	content := strings.Replace(apacheJavaContent, "SPDX-License-Identifier: Apache-2.0", "SPDX-License-Identifier: MIT", 1)
+
+	// When..
+	checkError := NewJavaFileChecker().CheckFileContent(content, "Other.java")
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.False(t, FindCheckDirective(content).IsSuppressing(content))
+}
+
+func TestAllowLicenseDirectiveNeedsWholeLicenseId(t *testing.T) {
+	// Given
+	content := "# copyright-check: allow-license GPL-2.0\n# SPDX-License-Identifier: GPL-2.0-only\nkey: value\n"
+
+	// When..
+	checkError := NewYamlFileChecker().CheckFileContent(content, "test.yaml")
+
+	// Then...
+	assert.NotNil(t, checkError)
+}
+
+func TestAllowLicenseDirectiveForUnknownLicenseIsAProblem(t *testing.T) {
+	// Given
+	content := strings.Replace(apacheJavaContent, "allow-license Apache-2.0", "allow-license Apache-2", 1)
+
+	// When..
+	checkError := NewJavaFileChecker().CheckFileContent(content, "Other.java")
+	directive := FindCheckDirective(content)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "The copyright-check directive allows licence 'Apache-2', which is not valid")
+	assert.Equal(t, 6, checkError.StartLine)
+	assert.False(t, directive.IsSuppressing(content))
+}
+
+func TestCheckerUsesTheDirectiveItIsGiven(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(DirectiveFileChecker)
+	directive := FindCheckDirective("// copyright-check: ignore\n")
+	content := "package dev.galasa;\n"
+
+	// When..
+	checkErrorWithDirective := checker.CheckFileContentWithDirective(content, "Test.java", directive)
+	checkErrorWithoutDirective := checker.CheckFileContentWithDirective(content, "Test.java", nil)
+
+	// Then...
+	assert.Nil(t, checkErrorWithDirective)
+	assert.NotNil(t, checkErrorWithoutDirective)
+}
+
+func TestFixerLeavesSuppressedFileAlone(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+
+	// When..
+	fixedContent, err := checker.FixFileContent(apacheJavaContent, "Other.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, apacheJavaContent, fixedContent)
+}
+
+// This is at the end of the file so the directive in it isn't in the top lines of this file, and doesn't suppress its own check.
+const apacheJavaContent = `/*
+ * Copyright 2019 The Other Project Authors
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+// copyright-check: allow-license Apache-2.0
+package org.other;
+`
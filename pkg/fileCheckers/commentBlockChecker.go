@@ -6,32 +6,45 @@
 package fileCheckers
 
 import (
-	"regexp"
-
 	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
 )
 
-func checkCommentBlock(commentBlock *string, fileName string, copyrightPattern *regexp.Regexp, expectedCopyrightMessage string) *checkTypes.CheckError {
+// Checks the first comment block of a file has exactly one copyright in it.
+// The comment block is content[blockStart:blockEnd], so any problem found can be located within the whole file.
+func checkCommentBlock(content string, blockStart int, blockEnd int, fileName string, copyrightPattern *headerPattern, expectedCopyrightMessage string) *checkTypes.CheckError {
 	var checkError *checkTypes.CheckError = nil
 	var copyrights [][]int
 
+	commentBlock := content[blockStart:blockEnd]
+
 	// Check to see if it has the copyright text
-	copyrights = copyrightPattern.FindAllStringSubmatchIndex(*commentBlock, -1)
+	copyrights = copyrightPattern.findAll(commentBlock)
 
 	if len(copyrights) <= 0 {
-		checkError = checkTypes.NewCheckError(
+		// Point at the whole comment block.
+		checkError = checkTypes.NewCheckErrorForRange(
 			fileName,
 			"Did not find copyright text in first comment block."+expectedCopyrightMessage,
-			0,
+			content,
+			blockStart,
+			blockEnd,
 		)
+		checkError.IsMissingHeader = true
 	}
 
 	if len(copyrights) > 1 {
-		checkError = &checkTypes.CheckError{
-			Path:     fileName,
-			Message:  "Found too many copyright texts in first comment block" + expectedCopyrightMessage,
-			Location: 0,
-		}
+		// Point at the first duplicate copyright.
+		checkError = checkTypes.NewCheckErrorForRange(
+			fileName,
+			"Found too many copyright texts in first comment block"+expectedCopyrightMessage,
+			content,
+			blockStart+copyrights[1][0],
+			blockStart+copyrights[1][1],
+		)
+	}
+
+	if len(copyrights) == 1 {
+		checkError = copyrightPattern.checkLicense(content, blockStart, copyrights[0], fileName, expectedCopyrightMessage)
 	}
 
 	return checkError
@@ -0,0 +1,170 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+	"github.com/galasa-dev/githubapp-copyright/pkg/spdx"
+)
+
+const (
+	DEFAULT_COPYRIGHT_HOLDER = "Copyright contributors to the Galasa project"
+	DEFAULT_LICENSE_ID       = "EPL-2.0"
+)
+
+// The text which every header is expected to contain.
+type CopyrightHeader struct {
+	// The copyright statement line. eg: "Copyright contributors to the Galasa project"
+	Holder string
+
+	// The SPDX licence expression new headers are given. eg: "EPL-2.0"
+	LicenseId string
+
+	// Other SPDX licence expressions a header can have instead. eg: "EPL-2.0 OR Apache-2.0"
+	OtherLicenseIds []string
+
+	// The lines of headers which files had before, which this header replaces. eg: "(?i)licensed materials - property of ibm"
+	// The Galasa holder has the IBM headers built in, and the policy can add more.
+	LegacyLinePatterns []*regexp.Regexp
+}
+
+func NewDefaultCopyrightHeader() CopyrightHeader {
+	return CopyrightHeader{
+		Holder:             DEFAULT_COPYRIGHT_HOLDER,
+		LicenseId:          DEFAULT_LICENSE_ID,
+		LegacyLinePatterns: GetBuiltInLegacyLinePatterns(DEFAULT_COPYRIGHT_HOLDER),
+	}
+}
+
+// Finds the copyright in a comment block, and the licence expression which goes with it.
+type headerPattern struct {
+	regex  *regexp.Regexp
+	header CopyrightHeader
+}
+
+// The name of the group in a header pattern which matches the licence expression.
+const LICENSE_GROUP_NAME = "license"
+
+// What an SPDX licence expression can look like, up to the end of its line. eg: "(EPL-2.0 OR MIT) AND Apache-2.0"
+// It can't end in a space or '-', so the end of a comment such as " -->" isn't part of it.
+const LICENSE_EXPRESSION_REGEX = `[A-Za-z0-9(]([A-Za-z0-9.+:() \t-]*[A-Za-z0-9+)])?`
+
+// Builds a pattern which finds the holder text followed by
+// any number of lines with leading and trailing whitespace around the comment character, followed by
+// a line containing <optional-whitespace>SPDX-License-Identifier:<optional-whitespace><licence expression>
+// Any licence expression is matched, so the pattern can say which licence it found if it isn't allowed.
+//
+// \s means any whitespace character (including \n new lines)
+func (header CopyrightHeader) buildCopyrightPattern(commentChar string) *headerPattern {
+	return header.buildCopyrightPatternForCommentRegex(regexp.QuoteMeta(commentChar))
+}
+
+// The same as buildCopyrightPattern, for comment lines which can start in more than one way.
+// eg: `(?i:rem|::)` for batch files
+func (header CopyrightHeader) buildCopyrightPatternForCommentRegex(commentRegex string) *headerPattern {
+	return &headerPattern{
+		regex: regexp.MustCompile(
+			regexp.QuoteMeta(header.Holder) +
+				`(\s*` + commentRegex + `\s*)*\s*` + commentRegex +
+				`\s*SPDX-License-Identifier:[ \t]*(?P<` + LICENSE_GROUP_NAME + `>` + LICENSE_EXPRESSION_REGEX + `)`),
+		header: header,
+	}
+}
+
+// Finds all the copyrights in the text. Each is a list of offsets, as from regexp.FindAllStringSubmatchIndex
+func (this *headerPattern) findAll(text string) [][]int {
+	return this.regex.FindAllStringSubmatchIndex(text, -1)
+}
+
+// Checks the licence expression of a copyright found by findAll is allowed.
+// The copyright was found in content[offset:], so any problem can be located within the whole content.
+func (this *headerPattern) checkLicense(content string, offset int, copyright []int, fileName string, expectedCopyrightMessage string) *checkTypes.CheckError {
+	var checkError *checkTypes.CheckError = nil
+
+	group := this.regex.SubexpIndex(LICENSE_GROUP_NAME)
+	licenseStart := offset + copyright[2*group]
+	licenseEnd := offset + copyright[2*group+1]
+
+	err := this.header.CheckLicense(content[licenseStart:licenseEnd])
+	if err != nil {
+		// Point at the licence expression.
+		checkError = checkTypes.NewCheckErrorForRange(fileName, err.Error()+expectedCopyrightMessage, content, licenseStart, licenseEnd)
+	}
+
+	return checkError
+}
+
+// Checks the licence expression from a header is a valid SPDX expression which the header allows.
+// Expressions which mean the same are allowed. eg: "Apache-2.0 OR EPL-2.0" for "EPL-2.0 OR Apache-2.0"
+func (header CopyrightHeader) CheckLicense(licenseText string) error {
+	var err error = nil
+
+	var license *spdx.Expression
+	license, err = spdx.ParseExpression(licenseText)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("SPDX-License-Identifier '%s' is not valid - %s.", licenseText, err.Error()))
+	} else {
+		allowedLicenseIds := append([]string{header.LicenseId}, header.OtherLicenseIds...)
+
+		isAllowed := false
+		for _, allowedLicenseId := range allowedLicenseIds {
+			allowedLicense, parseErr := spdx.ParseExpression(allowedLicenseId)
+			if parseErr == nil {
+				isAllowed = license.IsEquivalent(allowedLicense)
+			} else {
+				// The policy has already said what is wrong with it, so just compare the text.
+				isAllowed = (strings.TrimSpace(licenseText) == allowedLicenseId)
+			}
+			if isAllowed {
+				break
+			}
+		}
+
+		if !isAllowed {
+			err = errors.New(fmt.Sprintf("SPDX-License-Identifier '%s' is not allowed. Expected '%s'.", licenseText, strings.Join(allowedLicenseIds, "' or '")))
+		}
+	}
+
+	return err
+}
+
+// Builds the header which should be at the top of a file. eg: "/*\n * ...\n */"
+func (header CopyrightHeader) buildExpectedHeader(firstLine string, linePrefix string, lastLine string) string {
+	return firstLine + "\n" +
+		linePrefix + " " + header.Holder + "\n" +
+		linePrefix + "\n" +
+		linePrefix + " SPDX-License-Identifier: " + header.LicenseId + "\n" +
+		lastLine
+}
+
+// Builds the text we show to people so they know what the header should look like.
+// eg: "\nExpected to see:\n/*\n * ...\n */"
+func buildExpectedMessage(expectedHeader string) string {
+	return "\nExpected to see:\n" + expectedHeader
+}
+
+// Does a comment look like it was meant to be a copyright or licence header ?
+// If so, a fixer replaces it rather than adding another header above it.
+var headerLikeCommentPattern = regexp.MustCompile(`(?i)copyright|spdx-license-identifier|licen[cs]ed? under`)
+
+func isHeaderLikeComment(comment string) bool {
+	return headerLikeCommentPattern.MatchString(comment)
+}
+
+// The words and years in front of a copyright holder, which vary between ways of writing the same copyright.
+// eg: "SPDX-FileCopyrightText: 2023 " or "Copyright (c) 2021, 2023 "
+var copyrightPreamblePattern = regexp.MustCompile(`(?i)^(spdx-filecopyrighttext:|copyright|\(c\)|©|[0-9]{4}|[-,]|\s)+`)
+
+// Gets the holder from a copyright, so different ways of writing the same one can be compared.
+// eg: "contributors to the galasa project" for "Copyright contributors to the Galasa project"
+func NormaliseCopyrightText(copyright string) string {
+	return strings.ToLower(strings.TrimSpace(copyrightPreamblePattern.ReplaceAllString(copyright, "")))
+}
@@ -5,8 +5,51 @@
  */
 package fileCheckers
 
-import "github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
 
 type FileChecker interface {
 	CheckFileContent(content string, fileName string) *checkTypes.CheckError
 }
+
+// A file checker which honours copyright-check directives. The directive is found once for each file,
+// and given to the checker. It can be nil if the file doesn't have one.
+type DirectiveFileChecker interface {
+	FileChecker
+
+	CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError
+}
+
+// A file checker which can also correct the files it finds problems with.
+type FileFixer interface {
+	FileChecker
+
+	// Returns the content with the expected copyright header at the top,
+	// replacing any malformed or duplicate header which was there before.
+	// Content which already passes the check is returned unchanged.
+	FixFileContent(content string, fileName string) (string, error)
+}
+
+// Puts the header at the top of the content, on a line of its own.
+func prependHeader(header string, content string) string {
+	if strings.HasPrefix(content, "\n") || strings.HasPrefix(content, "\r\n") {
+		// The content already starts on a new line. eg: It followed an old header we took out.
+		return header + content
+	}
+	return header + "\n" + content
+}
+
+// Makes sure the fixed content passes the check, so we never make things worse.
+func checkFixWorked(checker FileChecker, fixedContent string, fileName string) error {
+	var err error = nil
+	checkError := checker.CheckFileContent(fixedContent, fileName)
+	if checkError != nil {
+		err = errors.New(fmt.Sprintf("Could not fix the copyright header of %s automatically. %s", fileName, checkError.Message))
+	}
+	return err
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The names by which a repository policy can refer to each kind of file checker.
+const (
+	// Files with a /* ... */ comment block at the top. eg: .java
+	CHECKER_KIND_BLOCK = "block"
+
+	// Files with a block of # comment lines at the top. eg: .yaml
+	CHECKER_KIND_HASH = "hash"
+
+	// Python files, with # comment lines after any #! and encoding lines.
+	CHECKER_KIND_PYTHON = "python"
+
+	// Files with a <!-- ... --> comment after any <?xml ...?> and <!DOCTYPE ...>. eg: .xml
+	CHECKER_KIND_XML = "xml"
+
+	// Files with a /* ... */ comment block or // comment lines at the top. eg: .c
+	CHECKER_KIND_C = "c"
+
+	// Go files, with a /* ... */ comment block or // comment lines after any build constraints.
+	CHECKER_KIND_GO = "go"
+
+	// Javascript and typescript files, with a /* ... */ comment block or // comment lines after any #! line.
+	CHECKER_KIND_JAVASCRIPT = "javascript"
+
+	// Groovy and kotlin scripts, with a /* ... */ comment block after any #! line. eg: .kts
+	CHECKER_KIND_GROOVY = "groovy"
+
+	// Files with a block of # or ! comment lines at the top. eg: .properties
+	CHECKER_KIND_PROPERTIES = "properties"
+
+	// Files with a block of ; or # comment lines at the top. eg: .ini
+	CHECKER_KIND_INI = "ini"
+
+	// Files with a block of -- comment lines at the top. eg: .sql
+	CHECKER_KIND_DOUBLE_DASH = "double-dash"
+
+	// PowerShell scripts, with a <# ... #> comment block or # comment lines at the top. eg: .ps1
+	CHECKER_KIND_POWERSHELL = "powershell"
+
+	// Windows batch files, with REM or :: comment lines after any @echo off line. eg: .bat
+	CHECKER_KIND_BATCH = "batch"
+
+	// REUSE sidecar files, which hold the copyright of the file they are named after. eg: logo.png.license
+	CHECKER_KIND_SIDECAR = "sidecar"
+
+	// Files which can't hold a header, so must be covered by a sidecar file, .reuse/dep5 or REUSE.toml. eg: .png
+	// Not used unless a repository policy asks for it.
+	CHECKER_KIND_REUSE = "reuse"
+
+	// Markdown files, with a <!-- ... --> comment or # comment lines in the front matter. eg: .md
+	// Not used unless a repository policy asks for it.
+	CHECKER_KIND_MARKDOWN = "markdown"
+)
+
+var fileCheckerFactories = map[string]func(header CopyrightHeader) FileChecker{
+	CHECKER_KIND_BLOCK:       NewJavaFileCheckerForHeader,
+	CHECKER_KIND_HASH:        NewYamlFileCheckerForHeader,
+	CHECKER_KIND_PYTHON:      NewPythonFileCheckerForHeader,
+	CHECKER_KIND_XML:         NewXmlFileCheckerForHeader,
+	CHECKER_KIND_C:           NewCFileCheckerForHeader,
+	CHECKER_KIND_GO:          NewGoFileCheckerForHeader,
+	CHECKER_KIND_JAVASCRIPT:  NewJavascriptFileCheckerForHeader,
+	CHECKER_KIND_GROOVY:      NewGroovyFileCheckerForHeader,
+	CHECKER_KIND_PROPERTIES:  NewPropertiesFileCheckerForHeader,
+	CHECKER_KIND_INI:         NewIniFileCheckerForHeader,
+	CHECKER_KIND_MARKDOWN:    NewMarkdownFileCheckerForHeader,
+	CHECKER_KIND_DOUBLE_DASH: NewDoubleDashFileCheckerForHeader,
+	CHECKER_KIND_POWERSHELL:  NewPowerShellFileCheckerForHeader,
+	CHECKER_KIND_BATCH:       NewBatchFileCheckerForHeader,
+	CHECKER_KIND_SIDECAR:     NewSidecarFileCheckerForHeader,
+	CHECKER_KIND_REUSE:       NewReuseOnlyFileCheckerForHeader,
+}
+
+// Creates a file checker of the named kind, which looks for the given header text.
+func NewFileCheckerOfKind(kind string, header CopyrightHeader) (FileChecker, error) {
+	var err error = nil
+	var checker FileChecker = nil
+
+	factory, isKnown := fileCheckerFactories[kind]
+	if isKnown {
+		checker = factory(header)
+	} else {
+		err = errors.New(fmt.Sprintf("Unknown file checker kind '%s'. Valid kinds are: %s", kind, strings.Join(GetFileCheckerKinds(), ", ")))
+	}
+	return checker, err
+}
+
+// Gets the names of all the kinds of file checker, in alphabetical order.
+func GetFileCheckerKinds() []string {
+	kinds := make([]string, 0, len(fileCheckerFactories))
+	for kind := range fileCheckerFactories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// The file checker kind used for each file extension, unless a repository policy says otherwise.
+func GetDefaultCheckerKindsByExtension() map[string]string {
+	return map[string]string{
+		".java":       CHECKER_KIND_BLOCK,
+		".gradle":     CHECKER_KIND_BLOCK,
+		".kt":         CHECKER_KIND_BLOCK,
+		".scala":      CHECKER_KIND_BLOCK,
+		".groovy":     CHECKER_KIND_GROOVY,
+		".kts":        CHECKER_KIND_GROOVY,
+		".go":         CHECKER_KIND_GO,
+		".ts":         CHECKER_KIND_JAVASCRIPT,
+		".tsx":        CHECKER_KIND_JAVASCRIPT,
+		".js":         CHECKER_KIND_JAVASCRIPT,
+		".yaml":       CHECKER_KIND_HASH,
+		".sh":         CHECKER_KIND_HASH,
+		".toml":       CHECKER_KIND_HASH,
+		".conf":       CHECKER_KIND_HASH,
+		".properties": CHECKER_KIND_PROPERTIES,
+		".ini":        CHECKER_KIND_INI,
+		".cfg":        CHECKER_KIND_INI,
+		".sql":        CHECKER_KIND_DOUBLE_DASH,
+		".lua":        CHECKER_KIND_DOUBLE_DASH,
+		".hs":         CHECKER_KIND_DOUBLE_DASH,
+		".ps1":        CHECKER_KIND_POWERSHELL,
+		".psm1":       CHECKER_KIND_POWERSHELL,
+		".bat":        CHECKER_KIND_BATCH,
+		".cmd":        CHECKER_KIND_BATCH,
+		".license":    CHECKER_KIND_SIDECAR,
+		".py":         CHECKER_KIND_PYTHON,
+		".xml":        CHECKER_KIND_XML,
+		".html":       CHECKER_KIND_XML,
+		".xsd":        CHECKER_KIND_XML,
+		".xsl":        CHECKER_KIND_XML,
+		".svg":        CHECKER_KIND_XML,
+		".c":          CHECKER_KIND_C,
+		".h":          CHECKER_KIND_C,
+		".cpp":        CHECKER_KIND_C,
+		".hpp":        CHECKER_KIND_C,
+		".cc":         CHECKER_KIND_C,
+		".mk":         CHECKER_KIND_HASH,
+		".dockerfile": CHECKER_KIND_HASH,
+	}
+}
+
+// The file checker kind used for files with particular names, whatever their extension.
+// The names can have * and ? wildcards. eg: "Dockerfile.*"
+func GetDefaultCheckerKindsByFileName() map[string]string {
+	return map[string]string{
+		"Dockerfile":    CHECKER_KIND_HASH,
+		"Dockerfile.*":  CHECKER_KIND_HASH,
+		"Containerfile": CHECKER_KIND_HASH,
+		"Makefile":      CHECKER_KIND_HASH,
+		"makefile":      CHECKER_KIND_HASH,
+		"GNUmakefile":   CHECKER_KIND_HASH,
+		"Jenkinsfile":   CHECKER_KIND_GROOVY,
+	}
+}
+
+// The file checker kind used for files without an extension, based on the program named on their #! line.
+// See GetShebangInterpreter
+func GetDefaultCheckerKindsByInterpreter() map[string]string {
+	return map[string]string{
+		"sh":     CHECKER_KIND_HASH,
+		"bash":   CHECKER_KIND_HASH,
+		"zsh":    CHECKER_KIND_HASH,
+		"ksh":    CHECKER_KIND_HASH,
+		"dash":   CHECKER_KIND_HASH,
+		"perl":   CHECKER_KIND_HASH,
+		"ruby":   CHECKER_KIND_HASH,
+		"python": CHECKER_KIND_PYTHON,
+		"node":   CHECKER_KIND_JAVASCRIPT,
+		"groovy": CHECKER_KIND_GROOVY,
+		"kotlin": CHECKER_KIND_GROOVY,
+		"lua":    CHECKER_KIND_DOUBLE_DASH,
+		"pwsh":   CHECKER_KIND_POWERSHELL,
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanCreateEachKindOfFileChecker(t *testing.T) {
+	for _, kind := range GetFileCheckerKinds() {
+		checker, err := NewFileCheckerOfKind(kind, NewDefaultCopyrightHeader())
+		assert.Nil(t, err, kind)
+		assert.NotNil(t, checker, kind)
+	}
+}
+
+func TestUnknownFileCheckerKindGivesError(t *testing.T) {
+	_, err := NewFileCheckerOfKind("garbage", NewDefaultCopyrightHeader())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Unknown file checker kind 'garbage'")
+}
+
+func TestDefaultExtensionsAllUseKnownKinds(t *testing.T) {
+	for extension, kind := range GetDefaultCheckerKindsByExtension() {
+		_, err := NewFileCheckerOfKind(kind, NewDefaultCopyrightHeader())
+		assert.Nil(t, err, extension)
+	}
+}
+
+func TestCheckerForCustomHeaderFindsCustomCopyright(t *testing.T) {
+	// Given
+	header := CopyrightHeader{Holder: "Copyright Acme Corp.", LicenseId: "Apache-2.0"}
+	checker, _ := NewFileCheckerOfKind(CHECKER_KIND_BLOCK, header)
+	var content = `/*
+ * Copyright Acme Corp.
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+`
+	// When..
+	checkError := checker.CheckFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckerForCustomHeaderRejectsGalasaCopyright(t *testing.T) {
+	// Given
+	header := CopyrightHeader{Holder: "Copyright Acme Corp.", LicenseId: "Apache-2.0"}
+	checker, _ := NewFileCheckerOfKind(CHECKER_KIND_HASH, header)
+	var content = `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+`
+	// When..
+	checkError := checker.CheckFileContent(content, "test.yaml")
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "# Copyright Acme Corp.\n#\n# SPDX-License-Identifier: Apache-2.0")
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedJavaHeader = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */`
+
+const expectedHashHeader = `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#`
+
+func TestFixJavaContentWhichIsOkLeavesItUnchanged(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+	content := expectedJavaHeader + "\npackage dev.galasa;\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, content, fixedContent)
+}
+
+func TestFixJavaContentWithNoCommentInsertsHeader(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+	content := "package dev.galasa;\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJavaHeader+"\npackage dev.galasa;\n", fixedContent)
+}
+
+func TestFixJavaContentKeepsNonCopyrightCommentBelowHeader(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+	content := "/* Some notes about this file */\npackage dev.galasa;\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJavaHeader+"\n/* Some notes about this file */\npackage dev.galasa;\n", fixedContent)
+}
+
+func TestFixJavaContentReplacesMalformedHeader(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+	content := `/*
+ * Copyright contributors to the Galasa project
+ */
+
+package dev.galasa;
+`
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJavaHeader+"\n\npackage dev.galasa;\n", fixedContent)
+}
+
+func TestFixJavaContentReplacesDuplicateHeader(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+	content := `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ *
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package dev.galasa;
+`
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJavaHeader+"\npackage dev.galasa;\n", fixedContent)
+}
+
+func TestFixJavaContentMovesMisplacedHeaderToTheTop(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+	content := "package dev.galasa;\n" + expectedJavaHeader + "\nimport java.util.List;\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJavaHeader+"\npackage dev.galasa;\nimport java.util.List;\n", fixedContent)
+}
+
+func TestFixYamlContentWithNoCommentInsertsHeader(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker().(FileFixer)
+	content := "key: value\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.yaml")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedHashHeader+"\nkey: value\n", fixedContent)
+}
+
+func TestFixYamlContentReplacesMalformedHeader(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker().(FileFixer)
+	content := "# Copyright contributors to the Galasa project\nkey: value\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.yaml")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedHashHeader+"\nkey: value\n", fixedContent)
+}
+
+func TestFixYamlContentKeepsNonCopyrightCommentBelowHeader(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker().(FileFixer)
+	content := "# The settings for the build\nkey: value\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.yaml")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedHashHeader+"\n# The settings for the build\nkey: value\n", fixedContent)
+}
+
+func TestFixShellScriptKeepsShebangLineAtTheTop(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker().(FileFixer)
+	content := "#!/usr/bin/env bash\n\necho hello\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.sh")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "#!/usr/bin/env bash\n\n"+expectedHashHeader+"\necho hello\n", fixedContent)
+}
+
+func TestFixShellScriptReplacesMalformedHeaderAfterShebang(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker().(FileFixer)
+	content := "#!/bin/bash\n# Copyright IBM Corp. 2021\necho hello\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.sh")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "#!/bin/bash\n\n"+expectedHashHeader+"\necho hello\n", fixedContent)
+}
+
+func TestFixJavaContentReplacesOnlyTheFirstOfSeveralCommentBlocks(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker().(FileFixer)
+	content := "/*\n * Copyright contributors to the Galasa project\n */\n/* Some notes about this file */\npackage dev.galasa;\n/* More notes */\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "test.java")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedJavaHeader+"\n/* Some notes about this file */\npackage dev.galasa;\n/* More notes */\n", fixedContent)
+}
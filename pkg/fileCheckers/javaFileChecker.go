@@ -7,62 +7,136 @@ package fileCheckers
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
 )
 
 type JavaFileChecker struct {
 	javaCommentBlockPattern      *regexp.Regexp
-	javaCopyrightPattern         *regexp.Regexp
+	javaHeaderCommentPattern     *regexp.Regexp
+	javaCopyrightPattern         *headerPattern
+	javaExpectedCopyrightHeader  string
 	javaExpectedCopyrightMessage string
+
+	// Scripts can have a #! line above the comment block. eg: A groovy script
+	isShebangAllowed bool
 }
 
 func NewJavaFileChecker() FileChecker {
+	return NewJavaFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewJavaFileCheckerForHeader(header CopyrightHeader) FileChecker {
 	this := new(JavaFileChecker)
 
-	// \s means any whitespace character (including \n new lines)
-	// [*] means a splat/star/asterisk character.
-	// We are trying to all this:
-	// A copyright message "Copyright contributors to the Galasa project" followed by
+	// We are trying to find the copyright holder text followed by
 	// any number of lines with leading and trailing whitespace around an asterisk, followed by
 	// a line containing <optional-whitespace>SPDX-License-Identifier:<optional-whitespace>EPL-2.0
-	this.javaCopyrightPattern = regexp.MustCompile(`Copyright contributors to the Galasa project(\s*[*]\s*)*\s*[*]\s*SPDX-License-Identifier:\s*EPL-2[.]0`)
+	this.javaCopyrightPattern = header.buildCopyrightPattern("*")
 
 	this.javaCommentBlockPattern = regexp.MustCompile(`\s*\/[*]((.|\s)*)[*]\/`)
 
-	this.javaExpectedCopyrightMessage = "\nExpected to see:\n/*\n * Copyright contributors to the Galasa project\n *\n * SPDX-License-Identifier: EPL-2.0\n */"
+	// The fixer only takes out the first /* ... */ comment, so the *? stops the match at the first */
+	this.javaHeaderCommentPattern = regexp.MustCompile(`\s*\/[*]((.|\s)*?)[*]\/`)
+
+	this.javaExpectedCopyrightHeader = header.buildExpectedHeader("/*", " *", " */")
+	this.javaExpectedCopyrightMessage = buildExpectedMessage(this.javaExpectedCopyrightHeader)
 
 	return this
 }
 
+// Checks groovy and kotlin scripts, which are like java files but can have a #! line first.
+func NewGroovyFileChecker() FileChecker {
+	return NewGroovyFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewGroovyFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := NewJavaFileCheckerForHeader(header).(*JavaFileChecker)
+	this.isShebangAllowed = true
+	return this
+}
+
 func (this *JavaFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *JavaFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
 
 	var checkError *checkTypes.CheckError = nil
 
-	commentBlockLocation := this.javaCommentBlockPattern.FindStringIndex(content)
+	headerStart := this.skipShebangLine(content)
+
+	commentBlockLocation := this.javaCommentBlockPattern.FindStringIndex(content[headerStart:])
+	if commentBlockLocation != nil {
+		commentBlockLocation[0] += headerStart
+		commentBlockLocation[1] += headerStart
+	}
 
 	if commentBlockLocation == nil {
 		checkError = &checkTypes.CheckError{
-			Path:     fileName,
-			Message:  "Did not find comment block." + this.javaExpectedCopyrightMessage,
-			Location: 0,
+			Path:            fileName,
+			Message:         "Did not find comment block." + this.javaExpectedCopyrightMessage,
+			Location:        0,
+			IsMissingHeader: true,
 		}
 	} else {
-		commentBlock := content[commentBlockLocation[0]:commentBlockLocation[1]]
-
-		checkError = checkCommentBlock(&commentBlock, fileName, this.javaCopyrightPattern, this.javaExpectedCopyrightMessage)
+		checkError = checkCommentBlock(content, commentBlockLocation[0], commentBlockLocation[1], fileName, this.javaCopyrightPattern, this.javaExpectedCopyrightMessage)
 
 		if checkError == nil {
 			// last check,  the first comment block should be at the top
-			if commentBlockLocation[0] != 0 {
-				checkError = &checkTypes.CheckError{
-					Path:     fileName,
-					Message:  "Comment block containing copyright should be at the top of the file." + this.javaExpectedCopyrightMessage,
-					Location: commentBlockLocation[0],
-				}
+			if commentBlockLocation[0] != headerStart {
+				// Point at the leading text above the comment block.
+				checkError = checkTypes.NewCheckErrorForRange(
+					fileName,
+					"Comment block containing copyright should be at the top of the file."+this.javaExpectedCopyrightMessage,
+					content,
+					headerStart,
+					commentBlockLocation[0],
+				)
 			}
 		}
 	}
 
 	return checkError
 }
+
+func (this *JavaFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		// Keep the #! line at the top of a script.
+		restStart := this.skipShebangLine(content)
+		shebangLine := strings.TrimRight(content[:restStart], " \t\r\n")
+		rest := content[restStart:]
+
+		// Take out any existing header, wherever it is, as it is wrong or in the wrong place.
+		commentBlockLocation := this.javaHeaderCommentPattern.FindStringIndex(rest)
+		if commentBlockLocation != nil && isHeaderLikeComment(rest[commentBlockLocation[0]:commentBlockLocation[1]]) {
+			rest = rest[:commentBlockLocation[0]] + rest[commentBlockLocation[1]:]
+		}
+
+		fixedContent = prependHeader(this.javaExpectedCopyrightHeader, rest)
+		if shebangLine != "" {
+			fixedContent = shebangLine + "\n\n" + fixedContent
+		}
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Gets the offset of the first non-whitespace character after the #! line, if there is one which is allowed.
+func (this *JavaFileChecker) skipShebangLine(content string) int {
+	offset := 0
+	if this.isShebangAllowed && strings.HasPrefix(content, "#!") {
+		offset = skipFirstLineAndWhitespace(content)
+	}
+	return offset
+}
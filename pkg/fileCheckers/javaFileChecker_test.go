@@ -181,3 +181,249 @@ func TestCheckJavaContentFindsCopyrightOkWhenOtherCommentsArePresent(t *testing.
 	// Then...
 	assert.Nil(t, checkError)
 }
+
+func TestCheckJavaContentFindsCopyrightInALaterCommentBlock(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `/* Some notes about this file */
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package dev.galasa;
+`
+	var fileName = "test.java"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	// The comment blocks are taken together, from the first /* to the last */
+	assert.Nil(t, checkError)
+}
+
+func TestCheckJavaContentFindsTooManyCopyrightAcrossCommentBlocks(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package dev.galasa;
+
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+`
+	var fileName = "test.java"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Found too many copyright texts in first comment block")
+}
+
+func TestCheckJavaContentWithLeadingTextPointsAtLeadingTextLines(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `leading text here
+and more leading text
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+`
+	var fileName = "test.java"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Equal(t, 1, checkError.StartLine)
+	assert.Equal(t, 2, checkError.EndLine)
+}
+
+func TestCheckJavaContentFindsTooManyCopyrightPointsAtDuplicate(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ *
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+`
+	var fileName = "test.java"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Equal(t, 6, checkError.StartLine)
+	assert.Equal(t, 4, checkError.StartColumn)
+	assert.Equal(t, 8, checkError.EndLine)
+}
+
+func TestCheckGroovyScriptFindsCopyrightOkAfterShebang(t *testing.T) {
+	// Given
+	checker := NewGroovyFileChecker()
+	var content = `#!/usr/bin/env groovy
+
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+println "hello"
+`
+	var fileName = "build.groovy"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckGroovyScriptWithoutShebangFindsCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewGroovyFileChecker()
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+plugins { id 'java' }
+`
+	var fileName = "build.gradle.kts"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckGroovyScriptWithTextAfterShebangFindsCopyrightNotAtTop(t *testing.T) {
+	// Given
+	checker := NewGroovyFileChecker()
+	var content = `#!/usr/bin/env kotlin
+println("hello")
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+`
+	var fileName = "script.main.kts"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Comment block containing copyright should be at the top of the file")
+	assert.Equal(t, 2, checkError.StartLine)
+}
+
+func TestCheckJavaContentDoesNotAllowShebang(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `#!/usr/bin/env java
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+`
+	var fileName = "Script.java"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Comment block containing copyright should be at the top of the file")
+}
+
+func TestFixGroovyScriptKeepsShebangLineAtTheTop(t *testing.T) {
+	// Given
+	checker := NewGroovyFileChecker().(FileFixer)
+	var content = "#!/usr/bin/env groovy\nprintln \"hello\"\n"
+	var fileName = "build.groovy"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "#!/usr/bin/env groovy\n\n"+expectedJavaHeader+"\nprintln \"hello\"\n", fixedContent)
+}
+
+func TestCheckJavaContentFindsLicenseNotAllowed(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+`
+	var fileName = "test.java"
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "SPDX-License-Identifier 'Apache-2.0' is not allowed. Expected 'EPL-2.0'.")
+	assert.Equal(t, 4, checkError.StartLine)
+}
+
+func TestCheckJavaContentFindsUnknownLicense(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0 OR Apache-3.0
+ */
+`
+	var fileName = "test.java"
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "SPDX-License-Identifier 'EPL-2.0 OR Apache-3.0' is not valid - 'Apache-3.0' is not a known SPDX licence identifier.")
+}
+
+func TestCheckJavaContentAllowsEquivalentLicenseExpression(t *testing.T) {
+	// Given
+	header := NewDefaultCopyrightHeader()
+	header.OtherLicenseIds = []string{"EPL-2.0 OR (MIT AND Apache-2.0)"}
+	checker := NewJavaFileCheckerForHeader(header)
+	var content = `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: (apache-2.0 AND MIT) OR EPL-2.0
+ */
+`
+	var fileName = "test.java"
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// How many lines at the top of a file we look in for a legacy header.
+const LEGACY_HEADER_LINE_COUNT = 20
+
+// The lines of the headers Galasa files had before they moved to "Copyright contributors to the Galasa project". eg:
+//
+//	/*
+//	 * Licensed Materials - Property of IBM
+//	 *
+//	 * (c) Copyright IBM Corp. 2019, 2021.
+//	 */
+var galasaLegacyHeaderLinePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)licensed materials\s*-\s*property of ibm`),
+	regexp.MustCompile(`(?i)(\(c\)\s*)?copyright\s+(\(c\)\s*)?([0-9]{4}\s*[-,]?\s*)*ibm corp(oration|\.)?`),
+	regexp.MustCompile(`(?i)us government users restricted rights`),
+	regexp.MustCompile(`(?i)disclosure restricted by gsa adp schedule contract`),
+}
+
+// Gets the lines of the legacy headers which are always migrated to a header with this holder.
+// Only the Galasa holder has any. Others are reported as third-party code unless the policy lists them.
+func GetBuiltInLegacyLinePatterns(holder string) []*regexp.Regexp {
+	legacyLinePatterns := make([]*regexp.Regexp, 0, len(galasaLegacyHeaderLinePatterns))
+	if holder == DEFAULT_COPYRIGHT_HOLDER {
+		legacyLinePatterns = append(legacyLinePatterns, galasaLegacyHeaderLinePatterns...)
+	}
+	return legacyLinePatterns
+}
+
+// A licence line in a legacy header, which the new header replaces too.
+var legacyLicenseLinePattern = regexp.MustCompile(`SPDX-License-Identifier:`)
+
+// A line of a comment which has nothing in it but the comment characters. eg: " *"
+var decorationLinePattern = regexp.MustCompile(`^[^A-Za-z0-9]*$`)
+
+// A legacy header found in a file, and the lines which should replace it.
+type LegacyHeader struct {
+	// Where the legacy lines are in the content, from the start of the first line to the end of the last,
+	// leaving out any comment characters which close the comment on the last line.
+	Start int
+	End   int
+
+	// The legacy lines, as they are in the content. eg: " * (c) Copyright IBM Corp. 2019."
+	OldText string
+
+	// The lines which replace them, with the same comment characters in front. eg: " * Copyright contributors to the Galasa project"
+	NewText string
+}
+
+// Finds a legacy header at the top of the content. Returns nil if there isn't one.
+// The header says which lines are part of a legacy header. If it has none, no legacy header is ever found.
+//
+// The legacy header is the lines from the first one which is part of a known legacy header, up to the last one
+// which is part of one or is a licence line. Lines which are just comment characters can be between them.
+func FindLegacyHeader(content string, header CopyrightHeader) *LegacyHeader {
+	var legacyHeader *LegacyHeader = nil
+
+	start := -1
+	end := -1
+	linePrefix := ""
+
+	lineStart := 0
+	for lineIndex := 0; lineIndex < LEGACY_HEADER_LINE_COUNT && lineStart < len(content); lineIndex++ {
+		lineEnd := strings.Index(content[lineStart:], "\n")
+		if lineEnd < 0 {
+			lineEnd = len(content)
+		} else {
+			lineEnd += lineStart
+		}
+		line := strings.TrimRight(content[lineStart:lineEnd], "\r")
+
+		legacyLocation := findLegacyHeaderLine(line, header.LegacyLinePatterns)
+		if legacyLocation != nil && start < 0 {
+			start = lineStart
+			linePrefix = line[:legacyLocation[0]]
+		}
+
+		if legacyLocation != nil || (start >= 0 && legacyLicenseLinePattern.MatchString(line)) {
+			end = lineStart + len(commentEndPattern.ReplaceAllString(line, ""))
+		} else if start >= 0 && !decorationLinePattern.MatchString(line) {
+			// Some other text, so the legacy header has finished.
+			break
+		}
+
+		lineStart = lineEnd + 1
+	}
+
+	if start >= 0 {
+		legacyHeader = new(LegacyHeader)
+		legacyHeader.Start = start
+		legacyHeader.End = end
+		legacyHeader.OldText = content[start:end]
+		// The new lines start with the same comment characters as the first legacy line.
+		legacyHeader.NewText = linePrefix + header.Holder + "\n" +
+			strings.TrimRight(linePrefix, " \t") + "\n" +
+			linePrefix + "SPDX-License-Identifier: " + header.LicenseId
+	}
+
+	return legacyHeader
+}
+
+// Gets where the text of a legacy header is in a line, or nil if the line isn't part of one.
+func findLegacyHeaderLine(line string, legacyLinePatterns []*regexp.Regexp) []int {
+	for _, pattern := range legacyLinePatterns {
+		location := pattern.FindStringIndex(line)
+		if location != nil {
+			return location
+		}
+	}
+	return nil
+}
+
+// Creates the check error for a file with a legacy header, which says exactly which lines to replace.
+func (this *LegacyHeader) NewCheckError(content string, fileName string) *checkTypes.CheckError {
+	checkError := checkTypes.NewCheckErrorForRange(fileName, "", content, this.Start, this.End)
+
+	lines := fmt.Sprintf("line %d", checkError.StartLine)
+	if checkError.EndLine > checkError.StartLine {
+		lines = fmt.Sprintf("lines %d-%d", checkError.StartLine, checkError.EndLine)
+	}
+
+	checkError.Message = fmt.Sprintf("Found a legacy copyright header, which should be migrated to the current one. Replace %s:\n%s\nwith:\n%s",
+		lines, this.OldText, this.NewText)
+	return checkError
+}
+
+// Replaces the legacy header in the content with the new lines.
+// The fix is checked with the file checker, so an error is returned if the new lines alone aren't enough.
+// eg: Because the legacy header isn't at the top of the file.
+func (this *LegacyHeader) FixFileContent(checker FileChecker, content string, fileName string) (string, error) {
+	fixedContent := content[:this.Start] + this.NewText + content[this.End:]
+	err := checkFixWorked(checker, fixedContent, fileName)
+	return fixedContent, err
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegacyHeaderInJavaCommentIsFoundAndMigrated(t *testing.T) {
+	// Given
+	checker := NewJavaFileChecker()
+	var content = `/*
+ * Licensed Materials - Property of IBM
+ *
+ * (c) Copyright IBM Corp. 2019, 2021.
+ */
+package dev.galasa;
+`
+	// When..
+	legacyHeader := FindLegacyHeader(content, NewDefaultCopyrightHeader())
+
+	// Then...
+	assert.NotNil(t, legacyHeader)
+	assert.Equal(t, " * Licensed Materials - Property of IBM\n *\n * (c) Copyright IBM Corp. 2019, 2021.", legacyHeader.OldText)
+
+	checkError := legacyHeader.NewCheckError(content, "test.java")
+	assert.Equal(t, 2, checkError.StartLine)
+	assert.Equal(t, 4, checkError.EndLine)
+	assert.Contains(t, checkError.Message, "Found a legacy copyright header, which should be migrated to the current one. Replace lines 2-4:\n"+
+		" * Licensed Materials - Property of IBM\n *\n * (c) Copyright IBM Corp. 2019, 2021.\nwith:\n"+
+		" * Copyright contributors to the Galasa project\n *\n * SPDX-License-Identifier: EPL-2.0")
+
+	fixedContent, err := legacyHeader.FixFileContent(checker, content, "test.java")
+	assert.Nil(t, err)
+	assert.Equal(t, `/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package dev.galasa;
+`, fixedContent)
+}
+
+func TestLegacyHeaderWithLicenseLineReplacesTheLicenseLineToo(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker()
+	var content = `# Copyright IBM Corp. 2020
+#
+# SPDX-License-Identifier: EPL-2.0
+
+name: test
+`
+	// When..
+	legacyHeader := FindLegacyHeader(content, NewDefaultCopyrightHeader())
+	fixedContent, err := legacyHeader.FixFileContent(checker, content, "test.yaml")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, `# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+
+name: test
+`, fixedContent)
+}
+
+func TestLegacyHeaderIsNotFoundInOtherHeaders(t *testing.T) {
+	// Given
+	var content = `/*
+ * Copyright 2019 Acme Corp
+ *
+ * Mentions IBM Corp. but isn't an IBM copyright.
+ */
+`
+	// When..
+	legacyHeader := FindLegacyHeader(content, NewDefaultCopyrightHeader())
+
+	// Then...
+	assert.Nil(t, legacyHeader)
+}
+
+func TestLegacyIbmHeaderIsNotFoundForOtherHolders(t *testing.T) {
+	// Given
+	var content = `/*
+ * Licensed Materials - Property of IBM
+ *
+ * (c) Copyright IBM Corp. 2019, 2021.
+ */
+`
+	// When..
+	header := CopyrightHeader{Holder: "Copyright Acme Corp", LicenseId: "MIT", LegacyLinePatterns: GetBuiltInLegacyLinePatterns("Copyright Acme Corp")}
+	legacyHeader := FindLegacyHeader(content, header)
+
+	// Then...
+	assert.Nil(t, legacyHeader)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// Checks files which have a block of comment lines at the top, each starting with the same prefix. eg: # in .yaml
+// Some formats allow more than one comment prefix. eg: # or ! in .properties
+type LineCommentFileChecker struct {
+	// The prefixes a comment line can start with. The first is the one we expect to see.
+	commentPrefixes []string
+
+	// The pattern which finds the copyright in a block of comment lines with each prefix.
+	copyrightPatternsByPrefix map[string]*headerPattern
+
+	expectedCopyrightHeader  string
+	expectedCopyrightMessage string
+}
+
+func NewYamlFileChecker() FileChecker {
+	return NewYamlFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewYamlFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	return NewLineCommentFileCheckerForHeader(header, "#")
+}
+
+// Checks .properties files, which can have # or ! comment lines.
+func NewPropertiesFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	return NewLineCommentFileCheckerForHeader(header, "#", "!")
+}
+
+// Checks .ini files, which can have ; or # comment lines.
+func NewIniFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	return NewLineCommentFileCheckerForHeader(header, ";", "#")
+}
+
+// Checks files with -- comment lines. eg: .sql
+func NewDoubleDashFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	return NewLineCommentFileCheckerForHeader(header, "--")
+}
+
+func NewLineCommentFileCheckerForHeader(header CopyrightHeader, commentPrefixes ...string) FileChecker {
+	this := new(LineCommentFileChecker)
+
+	this.commentPrefixes = commentPrefixes
+
+	// We are trying to find the copyright holder text followed by
+	// any number of lines with leading and trailing whitespace around a comment prefix, followed by
+	// a line containing <optional-whitespace>SPDX-License-Identifier:<optional-whitespace>EPL-2.0
+	this.copyrightPatternsByPrefix = make(map[string]*headerPattern)
+	for _, prefix := range commentPrefixes {
+		this.copyrightPatternsByPrefix[prefix] = header.buildCopyrightPattern(prefix)
+	}
+
+	expectedPrefix := commentPrefixes[0]
+	this.expectedCopyrightHeader = header.buildExpectedHeader(expectedPrefix, expectedPrefix, expectedPrefix)
+	this.expectedCopyrightMessage = buildExpectedMessage(this.expectedCopyrightHeader)
+
+	return this
+}
+
+func (this *LineCommentFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *LineCommentFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
+
+	var checkError *checkTypes.CheckError = nil
+
+	blockStart := 0
+
+	//if it is a bash script (.sh)
+	//ignore the first line that starts with !#
+	//and any subsequent whitespace
+	if isScript(content, fileName) {
+		blockStart = skipFirstLineAndWhitespace(content)
+	}
+
+	blockEnd, prefix := this.findCommentBlock(content, blockStart)
+
+	// check we have a comment block at the begining of the file
+	if blockEnd == blockStart {
+		checkError = &checkTypes.CheckError{
+			Path:            fileName,
+			Message:         "A comment block is missing at the start of the file." + this.expectedCopyrightMessage,
+			Location:        0,
+			IsMissingHeader: true,
+		}
+	} else {
+		checkError = checkCommentBlock(content, blockStart, blockEnd, fileName, this.copyrightPatternsByPrefix[prefix], this.expectedCopyrightMessage)
+	}
+
+	return checkError
+}
+
+func (this *LineCommentFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		// Keep the #! line at the top of a script.
+		shebangLine := ""
+		if strings.HasPrefix(content, "#!") {
+			restStart := skipFirstLineAndWhitespace(content)
+			shebangLine = strings.TrimRight(content[:restStart], " \t\r\n")
+			content = content[restStart:]
+		}
+
+		// Take out any existing header, as it is wrong.
+		blockEnd, _ := this.findCommentBlock(content, 0)
+		if blockEnd > 0 && isHeaderLikeComment(content[:blockEnd]) {
+			content = content[blockEnd:]
+		}
+
+		fixedContent = prependHeader(this.expectedCopyrightHeader, content)
+		if shebangLine != "" {
+			fixedContent = shebangLine + "\n\n" + fixedContent
+		}
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Finds the end of the block of comment lines which starts at the offset, and the prefix those lines start with.
+// All the lines in the block start with the same prefix as the first one.
+// Returns the starting offset if there is no comment line there.
+func (this *LineCommentFileChecker) findCommentBlock(content string, offset int) (int, string) {
+	blockEnd := offset
+	blockPrefix := ""
+	for _, prefix := range this.commentPrefixes {
+		blockEnd = findEndOfLinesWithPrefix(content, offset, prefix)
+		if blockEnd > offset {
+			blockPrefix = prefix
+			break
+		}
+	}
+	return blockEnd, blockPrefix
+}
+
+// Scripts have a #! line at the top, which isn't part of the header.
+// Scripts without an extension are recognised by the #! line alone.
+func isScript(content string, fileName string) bool {
+	return strings.HasSuffix(fileName, ".sh") || strings.HasPrefix(content, "#!")
+}
+
+// Gets the offset of the first non-whitespace character after the first line.
+func skipFirstLineAndWhitespace(content string) int {
+	offset := len(content)
+	nextLine := strings.Index(content, "\n")
+	if nextLine >= 0 {
+		offset = nextLine
+		for offset < len(content) && unicode.IsSpace(rune(content[offset])) {
+			offset++
+		}
+	}
+	return offset
+}
+
+// Starting at a line which begins at the offset, finds the end of the run of lines which all start with the prefix.
+// Returns the offset just after the last line of the run, or the starting offset if the first line doesn't start with the prefix.
+func findEndOfLinesWithPrefix(content string, offset int, prefix string) int {
+	for offset < len(content) {
+		lineEnd := strings.Index(content[offset:], "\n")
+		if lineEnd < 0 {
+			lineEnd = len(content)
+		} else {
+			lineEnd = offset + lineEnd + 1
+		}
+
+		if !strings.HasPrefix(content[offset:lineEnd], prefix) {
+			break
+		}
+		offset = lineEnd
+	}
+	return offset
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPropertiesContentFindsHashCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewPropertiesFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+framework.resultarchive.store=file:///tmp/ras
+`
+	var fileName = "cps.properties"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckPropertiesContentFindsExclamationCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewPropertiesFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `! Copyright contributors to the Galasa project
+!
+! SPDX-License-Identifier: EPL-2.0
+framework.resultarchive.store=file:///tmp/ras
+`
+	var fileName = "cps.properties"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckPropertiesContentWithMixedPrefixesOnlyLooksAtFirstBlock(t *testing.T) {
+	// Given
+	checker := NewPropertiesFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `# The settings for the CPS
+! Copyright contributors to the Galasa project
+!
+! SPDX-License-Identifier: EPL-2.0
+`
+	var fileName = "cps.properties"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in first comment block")
+	assert.Equal(t, 1, checkError.StartLine)
+	assert.Equal(t, 1, checkError.EndLine)
+}
+
+func TestCheckIniContentFindsSemicolonCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewIniFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `;
+; Copyright contributors to the Galasa project
+;
+; SPDX-License-Identifier: EPL-2.0
+;
+[section]
+key=value
+`
+	var fileName = "settings.ini"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckIniContentFindsHashCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewIniFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+[metadata]
+name = galasa
+`
+	var fileName = "setup.cfg"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckIniContentFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewIniFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `[section]
+key=value
+`
+	var fileName = "settings.ini"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+	assert.Contains(t, checkError.Message, "; Copyright contributors to the Galasa project")
+}
+
+func TestFixIniContentReplacesMalformedHeaderWithFirstPrefix(t *testing.T) {
+	// Given
+	checker := NewIniFileCheckerForHeader(NewDefaultCopyrightHeader()).(FileFixer)
+	var content = `# Copyright IBM Corp. 2021
+[section]
+`
+	var fileName = "settings.ini"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, ";\n; Copyright contributors to the Galasa project\n;\n; SPDX-License-Identifier: EPL-2.0\n;\n[section]\n", fixedContent)
+}
+
+func TestCheckSqlContentFindsCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewDoubleDashFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `--
+-- Copyright contributors to the Galasa project
+--
+-- SPDX-License-Identifier: EPL-2.0
+--
+CREATE TABLE runs (name VARCHAR(64));
+`
+	var fileName = "V1__create_runs.sql"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckLuaScriptFindsCopyrightOkAfterShebang(t *testing.T) {
+	// Given
+	checker := NewDoubleDashFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `#!/usr/bin/env lua
+
+-- Copyright contributors to the Galasa project
+--
+-- SPDX-License-Identifier: EPL-2.0
+print("hello")
+`
+	var fileName = "hello.lua"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckSqlContentFindsCopyrightMissing(t *testing.T) {
+	// Given
+	checker := NewDoubleDashFileCheckerForHeader(NewDefaultCopyrightHeader())
+	var content = `-- Creates the runs table
+CREATE TABLE runs (name VARCHAR(64));
+`
+	var fileName = "V1__create_runs.sql"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in first comment block")
+	assert.Contains(t, checkError.Message, "-- SPDX-License-Identifier: EPL-2.0")
+}
+
+func TestFixHaskellContentInsertsHeader(t *testing.T) {
+	// Given
+	checker := NewDoubleDashFileCheckerForHeader(NewDefaultCopyrightHeader()).(FileFixer)
+	var content = "module Main where\n"
+	var fileName = "Main.hs"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "--\n-- Copyright contributors to the Galasa project\n--\n-- SPDX-License-Identifier: EPL-2.0\n--\nmodule Main where\n", fixedContent)
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// Checks markdown files, which can have the header in a <!-- ... --> comment at the top,
+// or as # comment lines at the top of their YAML front matter.
+type MarkdownFileChecker struct {
+	htmlCopyrightPattern        *headerPattern
+	htmlExpectedCopyrightHeader string
+
+	frontMatterCopyrightPattern        *headerPattern
+	frontMatterExpectedCopyrightHeader string
+
+	expectedCopyrightMessage string
+
+	// MDX files can only have the header in their front matter.
+	mdxExpectedCopyrightMessage string
+}
+
+// The line which starts and ends the front matter of a markdown file.
+const FRONT_MATTER_DELIMITER = "---"
+
+func NewMarkdownFileChecker() FileChecker {
+	return NewMarkdownFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewMarkdownFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := new(MarkdownFileChecker)
+
+	this.htmlCopyrightPattern = header.buildCopyrightPattern("")
+	this.htmlExpectedCopyrightHeader = header.buildExpectedHeader("<!--", "", "-->")
+
+	this.frontMatterCopyrightPattern = header.buildCopyrightPattern("#")
+	this.frontMatterExpectedCopyrightHeader = header.buildExpectedHeader("#", "#", "#")
+
+	this.expectedCopyrightMessage = buildExpectedMessage(this.htmlExpectedCopyrightHeader) +
+		"\nor, at the top of the front matter:\n" +
+		FRONT_MATTER_DELIMITER + "\n" + this.frontMatterExpectedCopyrightHeader
+
+	this.mdxExpectedCopyrightMessage = "\nMDX files don't allow <!-- ... --> comments, so expected to see, at the top of the front matter:\n" +
+		FRONT_MATTER_DELIMITER + "\n" + this.frontMatterExpectedCopyrightHeader
+
+	return this
+}
+
+func (this *MarkdownFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *MarkdownFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
+
+	var checkError *checkTypes.CheckError = nil
+
+	var blockStart int
+	var blockEnd int
+	var copyrightPattern *headerPattern
+
+	expectedCopyrightMessage := this.expectedCopyrightMessage
+	if isMdxFile(fileName) {
+		expectedCopyrightMessage = this.mdxExpectedCopyrightMessage
+	}
+
+	if hasFrontMatter(content) {
+		blockStart = skipBlankLines(content, getNextLineOffset(content, 0))
+		blockEnd = findEndOfLinesWithPrefix(content, blockStart, "#")
+		copyrightPattern = this.frontMatterCopyrightPattern
+	} else if isMdxFile(fileName) {
+		// The header can't be in a <!-- ... --> comment, so there is no header at all.
+		blockStart = 0
+		blockEnd = 0
+	} else {
+		blockStart = skipWhitespace(content, 0)
+		blockEnd = findEndOfXmlComment(content, blockStart)
+		copyrightPattern = this.htmlCopyrightPattern
+	}
+
+	if blockEnd == blockStart {
+		checkError = &checkTypes.CheckError{
+			Path:            fileName,
+			Message:         "A comment block is missing at the start of the file." + expectedCopyrightMessage,
+			Location:        0,
+			IsMissingHeader: true,
+		}
+	} else {
+		checkError = checkCommentBlock(content, blockStart, blockEnd, fileName, copyrightPattern, expectedCopyrightMessage)
+	}
+
+	return checkError
+}
+
+func (this *MarkdownFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		if hasFrontMatter(content) {
+			// The header goes just inside the front matter.
+			frontMatterStart := getNextLineOffset(content, 0)
+			rest := content[skipBlankLines(content, frontMatterStart):]
+
+			// Take out any existing header, as it is wrong.
+			blockEnd := findEndOfLinesWithPrefix(rest, 0, "#")
+			if blockEnd > 0 && isHeaderLikeComment(rest[:blockEnd]) {
+				rest = rest[blockEnd:]
+			}
+
+			fixedContent = content[:frontMatterStart] + prependHeader(this.frontMatterExpectedCopyrightHeader, rest)
+
+		} else {
+			rest := content[skipWhitespace(content, 0):]
+
+			// Take out any existing header, as it is wrong.
+			commentEnd := findEndOfXmlComment(rest, 0)
+			if commentEnd > 0 && isHeaderLikeComment(rest[:commentEnd]) {
+				rest = rest[commentEnd:]
+			}
+
+			if isMdxFile(fileName) {
+				// MDX doesn't allow <!-- ... --> comments, so give the file some front matter to put the header in.
+				fixedContent = FRONT_MATTER_DELIMITER + "\n" + this.frontMatterExpectedCopyrightHeader + "\n" +
+					prependHeader(FRONT_MATTER_DELIMITER, rest)
+			} else {
+				fixedContent = prependHeader(this.htmlExpectedCopyrightHeader, rest)
+			}
+		}
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Does the content start with a --- line, which means it has front matter ?
+func hasFrontMatter(content string) bool {
+	firstLine := content[:getNextLineOffset(content, 0)]
+	return strings.TrimRight(firstLine, " \t\r\n") == FRONT_MATTER_DELIMITER
+}
+
+// Is the file MDX, which is markdown that doesn't allow <!-- ... --> comments ?
+func isMdxFile(fileName string) bool {
+	return strings.HasSuffix(fileName, ".mdx")
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMarkdownContentFindsHtmlCommentCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker()
+	var content = `<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+# Getting started
+`
+	var fileName = "docs/index.md"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckMarkdownContentFindsFrontMatterCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker()
+	var content = `---
+#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+title: Getting started
+---
+# Getting started
+`
+	var fileName = "docs/index.mdx"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckMarkdownContentWithHtmlCommentAfterFrontMatterFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker()
+	var content = `---
+title: Getting started
+---
+<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+`
+	var fileName = "docs/index.md"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+	assert.Contains(t, checkError.Message, "or, at the top of the front matter:")
+}
+
+func TestCheckMdxContentWithHtmlCommentCopyrightFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker()
+	var content = `<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+# Getting started
+`
+	var fileName = "docs/index.mdx"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+	assert.Contains(t, checkError.Message, "MDX files don't allow <!-- ... --> comments")
+}
+
+func TestFixMdxContentWithHtmlCommentCopyrightMovesItToFrontMatter(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker().(FileFixer)
+	var content = "<!--\n  Copyright contributors to the Galasa project\n\n  SPDX-License-Identifier: EPL-2.0\n-->\n# Getting started\n"
+	var fileName = "docs/index.mdx"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "---\n"+expectedHashHeader+"\n---\n# Getting started\n", fixedContent)
+}
+
+func TestCheckMarkdownContentFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker()
+	var content = `# Getting started
+`
+	var fileName = "docs/index.md"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckMarkdownContentFindsCopyrightMissingInFrontMatter(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker()
+	var content = `---
+# The page people see first
+title: Getting started
+---
+`
+	var fileName = "docs/index.md"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in first comment block")
+	assert.Equal(t, 2, checkError.StartLine)
+}
+
+func TestFixMarkdownContentInsertsHtmlComment(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker().(FileFixer)
+	var content = "# Getting started\n"
+	var fileName = "docs/index.md"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedXmlHeader+"\n# Getting started\n", fixedContent)
+}
+
+func TestFixMarkdownContentPutsHeaderInFrontMatter(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker().(FileFixer)
+	var content = "---\ntitle: Getting started\n---\n# Getting started\n"
+	var fileName = "docs/index.md"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "---\n"+expectedHashHeader+"\ntitle: Getting started\n---\n# Getting started\n", fixedContent)
+}
+
+func TestFixMdxContentWithoutFrontMatterAddsFrontMatter(t *testing.T) {
+	// Given
+	checker := NewMarkdownFileChecker().(FileFixer)
+	var content = "# Getting started\n"
+	var fileName = "docs/index.mdx"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "---\n"+expectedHashHeader+"\n---\n# Getting started\n", fixedContent)
+}
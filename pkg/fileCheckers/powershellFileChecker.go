@@ -0,0 +1,125 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// Checks PowerShell scripts, which have either a <# ... #> comment block or a block of # comment lines at the top.
+// The header can come after a #! line.
+type PowerShellFileChecker struct {
+	blockCopyrightPattern    *headerPattern
+	hashCopyrightPattern     *headerPattern
+	expectedCopyrightHeader  string
+	expectedCopyrightMessage string
+}
+
+func NewPowerShellFileChecker() FileChecker {
+	return NewPowerShellFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewPowerShellFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := new(PowerShellFileChecker)
+
+	// The lines inside a <# ... #> block have no comment character in front of them.
+	this.blockCopyrightPattern = header.buildCopyrightPattern("")
+	this.hashCopyrightPattern = header.buildCopyrightPattern("#")
+
+	// Either style is accepted, but we suggest # lines, as most scripts use them.
+	this.expectedCopyrightHeader = header.buildExpectedHeader("#", "#", "#")
+	this.expectedCopyrightMessage = buildExpectedMessage(this.expectedCopyrightHeader)
+
+	return this
+}
+
+func (this *PowerShellFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *PowerShellFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
+
+	var checkError *checkTypes.CheckError = nil
+
+	blockStart := this.skipShebangLine(content)
+	blockEnd, copyrightPattern := this.findHeaderComment(content, blockStart)
+
+	if blockEnd == blockStart {
+		checkError = &checkTypes.CheckError{
+			Path:            fileName,
+			Message:         "A comment block is missing at the start of the file." + this.expectedCopyrightMessage,
+			Location:        0,
+			IsMissingHeader: true,
+		}
+	} else {
+		checkError = checkCommentBlock(content, blockStart, blockEnd, fileName, copyrightPattern, this.expectedCopyrightMessage)
+	}
+
+	return checkError
+}
+
+func (this *PowerShellFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		// Keep the #! line at the top of a script.
+		restStart := this.skipShebangLine(content)
+		shebangLine := strings.TrimRight(content[:restStart], " \t\r\n")
+		rest := content[restStart:]
+
+		// Take out any existing header, as it is wrong.
+		blockEnd, _ := this.findHeaderComment(rest, 0)
+		if blockEnd > 0 && isHeaderLikeComment(rest[:blockEnd]) {
+			// Keep the new line at the end of a block of # lines, so any blank line after them stays.
+			rest = rest[len(strings.TrimRight(rest[:blockEnd], "\r\n")):]
+		}
+
+		fixedContent = prependHeader(this.expectedCopyrightHeader, rest)
+		if shebangLine != "" {
+			fixedContent = shebangLine + "\n\n" + fixedContent
+		}
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Gets the offset of the first non-whitespace character after the #! line, or at the start if there isn't one.
+func (this *PowerShellFileChecker) skipShebangLine(content string) int {
+	offset := skipWhitespace(content, 0)
+	if strings.HasPrefix(content, "#!") {
+		offset = skipFirstLineAndWhitespace(content)
+	}
+	return offset
+}
+
+// Finds the end of the <# ... #> comment block or block of # comment lines which starts at the offset,
+// and the pattern which finds the copyright inside that style of comment.
+// If there is no comment there, or it is never closed, the offset is returned unchanged.
+func (this *PowerShellFileChecker) findHeaderComment(content string, offset int) (int, *headerPattern) {
+	blockEnd := offset
+	copyrightPattern := this.blockCopyrightPattern
+
+	if strings.HasPrefix(content[offset:], "<#") {
+		closeIndex := strings.Index(content[offset+len("<#"):], "#>")
+		if closeIndex >= 0 {
+			blockEnd = offset + len("<#") + closeIndex + len("#>")
+		}
+	} else {
+		blockEnd = findEndOfLinesWithPrefix(content, offset, "#")
+		copyrightPattern = this.hashCopyrightPattern
+	}
+
+	return blockEnd, copyrightPattern
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPowerShellContent(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		expectedMessage string
+	}{
+		{
+			name: "hash lines copyright ok",
+			content: `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+Write-Host "hello"
+`,
+		},
+		{
+			name: "block comment copyright ok",
+			content: `<#
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+#>
+Write-Host "hello"
+`,
+		},
+		{
+			name: "copyright ok after shebang",
+			content: `#!/usr/bin/env pwsh
+
+<#
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+#>
+`,
+		},
+		{
+			name: "no comment",
+			content: `Write-Host "hello"
+`,
+			expectedMessage: "A comment block is missing at the start of the file",
+		},
+		{
+			name: "block comment never closed",
+			content: `<#
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+`,
+			expectedMessage: "A comment block is missing at the start of the file",
+		},
+		{
+			name: "help block without copyright",
+			content: `<#
+.SYNOPSIS
+  Installs Galasa.
+#>
+`,
+			expectedMessage: "Did not find copyright text in first comment block",
+		},
+		{
+			name: "too many copyrights",
+			content: `# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+`,
+			expectedMessage: "Found too many copyright texts in first comment block",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Given
+			checker := NewPowerShellFileChecker()
+
+			// When..
+			checkError := checker.CheckFileContent(test.content, "install.ps1")
+
+			// Then...
+			if test.expectedMessage == "" {
+				assert.Nil(t, checkError)
+			} else {
+				assert.NotNil(t, checkError)
+				assert.Contains(t, checkError.Message, test.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestFixPowerShellContentReplacesBlockCommentHeader(t *testing.T) {
+	// Given
+	checker := NewPowerShellFileChecker().(FileFixer)
+	var content = "<# Copyright IBM Corp. 2021 #>\nWrite-Host \"hello\"\n"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, "install.ps1")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, expectedHashHeader+"\nWrite-Host \"hello\"\n", fixedContent)
+}
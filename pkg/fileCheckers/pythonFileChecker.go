@@ -0,0 +1,171 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// Checks python files, which have a block of # comment lines at the top.
+// The header can come after a #! line and a PEP 263 encoding line, which python needs to be first.
+type PythonFileChecker struct {
+	hashCopyrightPattern         *headerPattern
+	hashExpectedCopyrightHeader  string
+	hashExpectedCopyrightMessage string
+
+	// eg: "# -*- coding: utf-8 -*-"
+	encodingLinePattern *regexp.Regexp
+
+	// The start of a module docstring. eg: """ or r'''
+	docstringStartPattern *regexp.Regexp
+}
+
+func NewPythonFileChecker() FileChecker {
+	return NewPythonFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewPythonFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := new(PythonFileChecker)
+
+	this.hashCopyrightPattern = header.buildCopyrightPattern("#")
+	this.hashExpectedCopyrightHeader = header.buildExpectedHeader("#", "#", "#")
+	this.hashExpectedCopyrightMessage = buildExpectedMessage(this.hashExpectedCopyrightHeader)
+
+	// The pattern PEP 263 says python uses to find the encoding of a file.
+	this.encodingLinePattern = regexp.MustCompile(`^[ \t\f]*#.*?coding[:=][ \t]*[-_.a-zA-Z0-9]+`)
+
+	this.docstringStartPattern = regexp.MustCompile(`^[rRuU]?("""|''')`)
+
+	return this
+}
+
+func (this *PythonFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *PythonFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
+
+	var checkError *checkTypes.CheckError = nil
+
+	blockStart := this.skipPreamble(content)
+	blockStart = skipBlankLines(content, blockStart)
+
+	blockEnd := findEndOfLinesWithPrefix(content, blockStart, "#")
+
+	if blockEnd == blockStart {
+		docstringStart, docstringEnd := this.findDocstring(content, blockStart)
+		if docstringEnd > docstringStart && isHeaderLikeComment(content[docstringStart:docstringEnd]) {
+			checkError = checkTypes.NewCheckErrorForRange(
+				fileName,
+				"The copyright should be in # comments at the start of the file, not in the module docstring."+this.hashExpectedCopyrightMessage,
+				content,
+				docstringStart,
+				docstringEnd,
+			)
+		} else {
+			checkError = &checkTypes.CheckError{
+				Path:            fileName,
+				Message:         "A comment block is missing at the start of the file." + this.hashExpectedCopyrightMessage,
+				Location:        0,
+				IsMissingHeader: true,
+			}
+		}
+	} else {
+		checkError = checkCommentBlock(content, blockStart, blockEnd, fileName, this.hashCopyrightPattern, this.hashExpectedCopyrightMessage)
+	}
+
+	return checkError
+}
+
+func (this *PythonFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		// The #! and encoding lines have to stay first.
+		preambleEnd := this.skipPreamble(content)
+		preamble := content[:preambleEnd]
+		if preamble != "" && !strings.HasSuffix(preamble, "\n") {
+			// The file is just a #! or encoding line, so the header has to go on a new line.
+			preamble += "\n"
+		}
+		rest := content[skipBlankLines(content, preambleEnd):]
+
+		// Take out any existing header, as it is wrong.
+		blockEnd := findEndOfLinesWithPrefix(rest, 0, "#")
+		if blockEnd > 0 && isHeaderLikeComment(rest[:blockEnd]) {
+			rest = rest[blockEnd:]
+		}
+
+		fixedContent = preamble + prependHeader(this.hashExpectedCopyrightHeader, rest)
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Gets the offset just after the #! line and the encoding line, if there are any.
+// PEP 263 says the encoding line has to be the first or second line.
+func (this *PythonFileChecker) skipPreamble(content string) int {
+	offset := 0
+
+	if strings.HasPrefix(content, "#!") {
+		offset = getNextLineOffset(content, offset)
+	}
+
+	if this.encodingLinePattern.MatchString(content[offset:getNextLineOffset(content, offset)]) {
+		offset = getNextLineOffset(content, offset)
+	}
+
+	return offset
+}
+
+// Finds the module docstring, if the content at the offset starts with one.
+func (this *PythonFileChecker) findDocstring(content string, offset int) (int, int) {
+	docstringEnd := offset
+
+	match := this.docstringStartPattern.FindStringSubmatchIndex(content[offset:])
+	if match != nil {
+		quotes := content[offset+match[2] : offset+match[3]]
+		closingQuotes := strings.Index(content[offset+match[1]:], quotes)
+		if closingQuotes < 0 {
+			docstringEnd = len(content)
+		} else {
+			docstringEnd = offset + match[1] + closingQuotes + len(quotes)
+		}
+	}
+
+	return offset, docstringEnd
+}
+
+// Gets the offset of the start of the line after the one the offset is in.
+func getNextLineOffset(content string, offset int) int {
+	lineEnd := strings.Index(content[offset:], "\n")
+	if lineEnd < 0 {
+		return len(content)
+	}
+	return offset + lineEnd + 1
+}
+
+// Gets the offset of the first line at or after the offset which isn't blank.
+func skipBlankLines(content string, offset int) int {
+	for offset < len(content) {
+		nextLine := getNextLineOffset(content, offset)
+		if strings.TrimSpace(content[offset:nextLine]) != "" {
+			break
+		}
+		offset = nextLine
+	}
+	return offset
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPythonContentFindsCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+import os
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckPythonContentFindsCopyrightOkAfterShebang(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `#!/usr/bin/env python3
+
+#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckPythonContentFindsCopyrightOkAfterShebangAndEncoding(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `#!/usr/bin/env python3
+# -*- coding: utf-8 -*-
+#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckPythonContentFindsCopyrightOkAfterEncodingOnFirstLine(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `# vim: set fileencoding=latin-1 :
+#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckPythonContentFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `import os
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckPythonContentRejectsCopyrightOnlyInDocstring(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `"""
+Copyright contributors to the Galasa project
+
+SPDX-License-Identifier: EPL-2.0
+"""
+import os
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "not in the module docstring")
+	assert.Equal(t, 1, checkError.StartLine)
+	assert.Equal(t, 5, checkError.EndLine)
+}
+
+func TestCheckPythonContentWithOrdinaryDocstringFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `'''Tools for running Galasa tests.'''
+import os
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckPythonContentFindsTooManyCopyright(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker()
+	var content = `#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+`
+	var fileName = "test.py"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Found too many copyright texts in first comment block")
+}
+
+func TestFixPythonContentKeepsShebangAndEncodingFirst(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker().(FileFixer)
+	var content = `#!/usr/bin/env python3
+# -*- coding: utf-8 -*-
+"""Copyright IBM Corp. 2021"""
+import os
+`
+	var fileName = "test.py"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "#!/usr/bin/env python3\n# -*- coding: utf-8 -*-\n"+expectedHashHeader+"\n\"\"\"Copyright IBM Corp. 2021\"\"\"\nimport os\n", fixedContent)
+}
+
+func TestFixPythonContentOfShebangWithoutNewLineKeepsShebangLine(t *testing.T) {
+	// Given
+	checker := NewPythonFileChecker().(FileFixer)
+	var content = "#!/usr/bin/env python"
+	var fileName = "test.py"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "#!/usr/bin/env python\n"+expectedHashHeader+"\n", fixedContent)
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// The extension of a REUSE sidecar file, which holds the copyright of the file it is named after.
+// eg: "logo.png.license" holds the copyright of "logo.png". See https://reuse.software/spec/
+const SIDECAR_FILE_EXTENSION = ".license"
+
+// Checks REUSE sidecar files, which contain nothing but the copyright and licence of another file.
+type SidecarFileChecker struct {
+	sidecarCopyrightPattern *headerPattern
+	sidecarExpectedContent  string
+	sidecarExpectedMessage  string
+}
+
+func NewSidecarFileChecker() FileChecker {
+	return NewSidecarFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewSidecarFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := new(SidecarFileChecker)
+
+	// There are no comment characters in a sidecar file.
+	this.sidecarCopyrightPattern = header.buildCopyrightPattern("")
+	this.sidecarExpectedContent = header.Holder + "\n\n" + "SPDX-License-Identifier: " + header.LicenseId + "\n"
+	this.sidecarExpectedMessage = buildExpectedMessage(this.sidecarExpectedContent)
+
+	return this
+}
+
+func (this *SidecarFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	var checkError *checkTypes.CheckError = nil
+
+	copyrights := this.sidecarCopyrightPattern.findAll(content)
+
+	if len(copyrights) <= 0 {
+		checkError = checkTypes.NewCheckErrorForRange(
+			fileName,
+			"Did not find copyright text in the "+SIDECAR_FILE_EXTENSION+" file."+this.sidecarExpectedMessage,
+			content,
+			0,
+			len(content),
+		)
+	}
+
+	if len(copyrights) > 1 {
+		checkError = checkTypes.NewCheckErrorForRange(
+			fileName,
+			"Found too many copyright texts in the "+SIDECAR_FILE_EXTENSION+" file."+this.sidecarExpectedMessage,
+			content,
+			copyrights[1][0],
+			copyrights[1][1],
+		)
+	}
+
+	if len(copyrights) == 1 {
+		checkError = this.sidecarCopyrightPattern.checkLicense(content, 0, copyrights[0], fileName, this.sidecarExpectedMessage)
+	}
+
+	return checkError
+}
+
+func (this *SidecarFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+		// The whole file is the header, so replace all of it.
+		fixedContent = this.sidecarExpectedContent
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Checks files which can't hold a copyright header of their own. eg: images
+// They only pass if REUSE information covers them, so the check of the content always fails.
+type ReuseOnlyFileChecker struct {
+	expectedMessage string
+}
+
+func NewReuseOnlyFileChecker() FileChecker {
+	return NewReuseOnlyFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewReuseOnlyFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := new(ReuseOnlyFileChecker)
+	sidecar := NewSidecarFileCheckerForHeader(header).(*SidecarFileChecker)
+	this.expectedMessage = sidecar.sidecarExpectedMessage
+	return this
+}
+
+func (this *ReuseOnlyFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return &checkTypes.CheckError{
+		Path: fileName,
+		Message: "This kind of file can't hold a copyright header. Put the header in a " + fileName + SIDECAR_FILE_EXTENSION +
+			" file, or cover the file in .reuse/dep5 or REUSE.toml." + this.expectedMessage,
+		Location:        0,
+		IsMissingHeader: true,
+	}
+}
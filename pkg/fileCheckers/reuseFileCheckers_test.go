@@ -0,0 +1,82 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSidecarFileWithCopyrightIsOk(t *testing.T) {
+	// Given
+	checker := NewSidecarFileChecker()
+
+	// When..
+	checkError := checker.CheckFileContent(`Copyright contributors to the Galasa project
+
+SPDX-License-Identifier: EPL-2.0
+`, "images/logo.png.license")
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestSidecarFileWithSpdxCopyrightTextIsOk(t *testing.T) {
+	// Given
+	checker := NewSidecarFileChecker()
+
+	// When..
+	checkError := checker.CheckFileContent(`SPDX-FileCopyrightText: Copyright contributors to the Galasa project
+
+SPDX-License-Identifier: EPL-2.0
+`, "images/logo.png.license")
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestSidecarFileWithWrongCopyrightFails(t *testing.T) {
+	// Given
+	checker := NewSidecarFileChecker()
+
+	// When..
+	checkError := checker.CheckFileContent(`SPDX-FileCopyrightText: 2023 Someone Else
+
+SPDX-License-Identifier: MIT
+`, "images/logo.png.license")
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in the .license file.")
+}
+
+func TestSidecarFileIsFixedByReplacingIt(t *testing.T) {
+	// Given
+	checker := NewSidecarFileChecker().(FileFixer)
+
+	// When..
+	fixedContent, err := checker.FixFileContent("SPDX-License-Identifier: MIT\n", "images/logo.png.license")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, `Copyright contributors to the Galasa project
+
+SPDX-License-Identifier: EPL-2.0
+`, fixedContent)
+}
+
+func TestReuseOnlyFileAlwaysFails(t *testing.T) {
+	// Given
+	checker := NewReuseOnlyFileChecker()
+
+	// When..
+	checkError := checker.CheckFileContent("\x89PNG", "images/logo.png")
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "images/logo.png.license")
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"path"
+	"strings"
+)
+
+// Gets the name of the program a script is run with, from the #! line at the top of it.
+// Any version number is dropped, so scripts for different versions of a language are treated alike.
+// eg: "bash" for "#!/bin/bash -e", "python" for "#!/usr/bin/env python3"
+// Returns "" if the content doesn't start with a #! line.
+func GetShebangInterpreter(content string) string {
+	interpreter := ""
+
+	if strings.HasPrefix(content, "#!") {
+		firstLine := content[len("#!"):getNextLineOffset(content, 0)]
+		words := strings.Fields(firstLine)
+
+		if len(words) > 0 {
+			interpreter = path.Base(words[0])
+
+			if interpreter == "env" {
+				// eg: "#!/usr/bin/env -S python3 -u" The program is the first word which isn't an option or a variable setting.
+				interpreter = ""
+				for _, word := range words[1:] {
+					if !strings.HasPrefix(word, "-") && !strings.Contains(word, "=") {
+						interpreter = path.Base(word)
+						break
+					}
+				}
+			}
+
+			interpreter = strings.TrimRight(interpreter, "0123456789.")
+		}
+	}
+
+	return interpreter
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShebangInterpreterIsProgramOnFirstLine(t *testing.T) {
+	assert.Equal(t, "bash", GetShebangInterpreter("#!/bin/bash\necho hello\n"))
+	assert.Equal(t, "bash", GetShebangInterpreter("#! /bin/bash -e\n"))
+	assert.Equal(t, "sh", GetShebangInterpreter("#!/bin/sh"))
+}
+
+func TestShebangInterpreterUsesProgramRunByEnv(t *testing.T) {
+	assert.Equal(t, "node", GetShebangInterpreter("#!/usr/bin/env node\n"))
+	assert.Equal(t, "python", GetShebangInterpreter("#!/usr/bin/env -S PYTHONUNBUFFERED=1 python3 -u\n"))
+}
+
+func TestShebangInterpreterDropsVersionNumber(t *testing.T) {
+	assert.Equal(t, "python", GetShebangInterpreter("#!/usr/bin/python3.11\n"))
+}
+
+func TestShebangInterpreterIsBlankWithoutShebang(t *testing.T) {
+	assert.Equal(t, "", GetShebangInterpreter("echo hello\n#!/bin/bash\n"))
+	assert.Equal(t, "", GetShebangInterpreter("#!\n"))
+	assert.Equal(t, "", GetShebangInterpreter(""))
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// How many lines at the top of a file we look in for the copyright and licence of someone else's code.
+const THIRD_PARTY_NOTICE_LINE_COUNT = 40
+
+// A copyright statement, which has a year or a (c) so that sentences which just mention copyright don't count.
+// eg: "Copyright 2019 Acme Corp", "Copyright (c) The Foo Authors", "Copyright Acme Corp. 2015, 2020"
+var copyrightStatementPattern = regexp.MustCompile(`(?i)(spdx-filecopyrighttext:|copyright\b)[^\n]*?([0-9]{4}|\(c\)|©)[^\n]*`)
+
+// The text which ends a comment, and so isn't part of a copyright statement on the same line.
+var commentEndPattern = regexp.MustCompile(`\s*(\*/|-->|#>)?\s*$`)
+
+// Licence notices which are often found in code from elsewhere, instead of an SPDX-License-Identifier.
+var licenseNotices = []struct {
+	license string
+	pattern *regexp.Regexp
+}{
+	{"Apache-2.0", regexp.MustCompile(`(?i)licensed under the apache license`)},
+	{"MIT", regexp.MustCompile(`(?i)permission is hereby granted, free of charge`)},
+	{"BSD", regexp.MustCompile(`(?i)redistribution and use in source and binary forms`)},
+	{"GPL", regexp.MustCompile(`(?i)the gnu (lesser |affero )?general public license`)},
+	{"MPL-2.0", regexp.MustCompile(`(?i)subject to the terms of the mozilla public license`)},
+}
+
+var spdxLicensePattern = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*(` + LICENSE_EXPRESSION_REGEX + `)`)
+
+// Looks at a file which failed the check, to see if it is someone else's code rather than ours without a header.
+// Code copied in from elsewhere has to be reviewed before it is added, as adding our header to it would be wrong.
+//
+// A file is third-party code if it has a copyright statement for another holder, or if it has a licence
+// which the header doesn't allow and doesn't mention our holder at all.
+// Returns nil if the file doesn't look like third-party code.
+func CheckThirdPartyCode(content string, fileName string, header CopyrightHeader) *checkTypes.CheckError {
+	var checkError *checkTypes.CheckError = nil
+
+	topLines := getTopLines(content, THIRD_PARTY_NOTICE_LINE_COUNT)
+	ownHolder := NormaliseCopyrightText(header.Holder)
+
+	foundNotices := make([]string, 0)
+	firstNoticeStart := -1
+	firstNoticeEnd := -1
+	addNotice := func(notice string, start int, end int) {
+		foundNotices = append(foundNotices, notice)
+		if firstNoticeStart < 0 || start < firstNoticeStart {
+			firstNoticeStart = start
+			firstNoticeEnd = end
+		}
+	}
+
+	for _, location := range copyrightStatementPattern.FindAllStringIndex(topLines, -1) {
+		statement := commentEndPattern.ReplaceAllString(topLines[location[0]:location[1]], "")
+		holder := NormaliseCopyrightText(statement)
+		if holder != "" && !strings.Contains(holder, ownHolder) {
+			addNotice("copyright '"+strings.TrimSpace(statement)+"'", location[0], location[0]+len(statement))
+		}
+	}
+
+	if !strings.Contains(strings.ToLower(topLines), ownHolder) {
+		for _, match := range spdxLicensePattern.FindAllStringSubmatchIndex(topLines, -1) {
+			license := topLines[match[2]:match[3]]
+			if header.CheckLicense(license) != nil {
+				addNotice("licence '"+license+"'", match[0], match[1])
+			}
+		}
+
+		for _, notice := range licenseNotices {
+			location := notice.pattern.FindStringIndex(topLines)
+			if location != nil {
+				addNotice("a licence notice for "+notice.license, location[0], location[1])
+			}
+		}
+	}
+
+	if len(foundNotices) > 0 {
+		checkError = checkTypes.NewCheckErrorForRange(
+			fileName,
+			"Third-party code: needs legal review. Found "+strings.Join(foundNotices, ", ")+
+				", so the file looks like it came from somewhere else, and shouldn't be given our copyright header. "+
+				"Once it has been approved, add a copyright-check directive to the file, or list it in .copyrightignore.",
+			content,
+			firstNoticeStart,
+			firstNoticeEnd,
+		)
+		checkError.IsThirdParty = true
+	}
+
+	return checkError
+}
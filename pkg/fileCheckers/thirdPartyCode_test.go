@@ -0,0 +1,76 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThirdPartyCodeWithForeignCopyrightAndLicenseIsFound(t *testing.T) {
+	// Given
+	var content = `/*
+ * Copyright 2019 Acme Corp
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ */
+package com.acme;
+`
+	// When..
+	checkError := CheckThirdPartyCode(content, "test.java", NewDefaultCopyrightHeader())
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.True(t, checkError.IsThirdParty)
+	assert.Contains(t, checkError.Message, "Third-party code: needs legal review.")
+	assert.Contains(t, checkError.Message, "copyright 'Copyright 2019 Acme Corp'")
+	assert.Contains(t, checkError.Message, "a licence notice for Apache-2.0")
+	assert.Equal(t, 2, checkError.StartLine)
+	assert.Nil(t, checkError.Fix)
+}
+
+func TestThirdPartyCodeWithForeignSpdxLicenseIsFound(t *testing.T) {
+	// Given
+	var content = `# SPDX-License-Identifier: MIT
+echo hello
+`
+	// When..
+	checkError := CheckThirdPartyCode(content, "test.sh", NewDefaultCopyrightHeader())
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "licence 'MIT'")
+}
+
+func TestThirdPartyCodeIsNotFoundInFileWithoutAHeader(t *testing.T) {
+	// Given
+	var content = `/*
+ * Works out the copyright year to use.
+ */
+package dev.galasa;
+`
+	// When..
+	checkError := CheckThirdPartyCode(content, "test.java", NewDefaultCopyrightHeader())
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestThirdPartyCodeIsNotFoundInOurOwnDatedHeader(t *testing.T) {
+	// Given
+	var content = `/*
+ * Copyright (c) 2023 contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+`
+	// When..
+	checkError := CheckThirdPartyCode(content, "test.java", NewDefaultCopyrightHeader())
+
+	// Then...
+	assert.Nil(t, checkError)
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/galasa-dev/githubapp-copyright/pkg/checkTypes"
+)
+
+// Checks XML and HTML files, which have a <!-- ... --> comment at the top.
+// The header can come after an <?xml ...?> prolog and a <!DOCTYPE ...>, which have to be first.
+type XmlFileChecker struct {
+	xmlCopyrightPattern         *headerPattern
+	xmlExpectedCopyrightHeader  string
+	xmlExpectedCopyrightMessage string
+
+	// The optional <?xml ...?> and <!DOCTYPE ...> at the top of the file, and the rest of the line after them.
+	preamblePattern *regexp.Regexp
+}
+
+func NewXmlFileChecker() FileChecker {
+	return NewXmlFileCheckerForHeader(NewDefaultCopyrightHeader())
+}
+
+func NewXmlFileCheckerForHeader(header CopyrightHeader) FileChecker {
+	this := new(XmlFileChecker)
+
+	// The lines inside an XML comment have no comment character in front of them.
+	this.xmlCopyrightPattern = header.buildCopyrightPattern("")
+
+	// A DOCTYPE can have an internal subset in [...], which can contain > characters.
+	this.preamblePattern = regexp.MustCompile(
+		`^(\s*<\?xml(.|\s)*?\?>)?` +
+			`(\s*<!(?i:doctype)[^>\[]*(\[(.|\s)*?\])?[^>]*>)?` +
+			`[ \t]*(\r?\n)?`)
+
+	this.xmlExpectedCopyrightHeader = header.buildExpectedHeader("<!--", "", "-->")
+	this.xmlExpectedCopyrightMessage = buildExpectedMessage(this.xmlExpectedCopyrightHeader)
+
+	return this
+}
+
+func (this *XmlFileChecker) CheckFileContent(content string, fileName string) *checkTypes.CheckError {
+	return this.CheckFileContentWithDirective(content, fileName, FindCheckDirective(content))
+}
+
+func (this *XmlFileChecker) CheckFileContentWithDirective(content string, fileName string, directive *CheckDirective) *checkTypes.CheckError {
+	if isDecided, checkError := directive.decidesCheck(content, fileName); isDecided {
+		return checkError
+	}
+
+	var checkError *checkTypes.CheckError = nil
+
+	blockStart := skipBlankLines(content, this.skipPreamble(content))
+	blockEnd := findEndOfXmlComment(content, blockStart)
+
+	if blockEnd == blockStart {
+		checkError = &checkTypes.CheckError{
+			Path:            fileName,
+			Message:         "A comment block is missing at the start of the file." + this.xmlExpectedCopyrightMessage,
+			Location:        0,
+			IsMissingHeader: true,
+		}
+	} else {
+		checkError = checkCommentBlock(content, blockStart, blockEnd, fileName, this.xmlCopyrightPattern, this.xmlExpectedCopyrightMessage)
+	}
+
+	return checkError
+}
+
+func (this *XmlFileChecker) FixFileContent(content string, fileName string) (string, error) {
+	var err error = nil
+	fixedContent := content
+
+	if this.CheckFileContent(content, fileName) != nil {
+
+		// The <?xml ...?> prolog and the <!DOCTYPE ...> have to stay first.
+		preambleEnd := this.skipPreamble(content)
+		preamble := content[:preambleEnd]
+		if preamble != "" && !strings.HasSuffix(preamble, "\n") {
+			preamble += "\n"
+		}
+		rest := content[skipBlankLines(content, preambleEnd):]
+
+		// Take out any existing header, as it is wrong.
+		commentEnd := findEndOfXmlComment(rest, 0)
+		if commentEnd > 0 && isHeaderLikeComment(rest[:commentEnd]) {
+			rest = rest[commentEnd:]
+		}
+
+		fixedContent = preamble + prependHeader(this.xmlExpectedCopyrightHeader, rest)
+
+		err = checkFixWorked(this, fixedContent, fileName)
+	}
+
+	return fixedContent, err
+}
+
+// Gets the offset just after the <?xml ...?> prolog and the <!DOCTYPE ...>, if there are any.
+func (this *XmlFileChecker) skipPreamble(content string) int {
+	return len(this.preamblePattern.FindString(content))
+}
+
+// Gets the offset just after the <!-- ... --> comment which starts at the offset.
+// If there is no comment there, or it is never closed, the offset is returned unchanged.
+func findEndOfXmlComment(content string, offset int) int {
+	commentEnd := offset
+	if strings.HasPrefix(content[offset:], "<!--") {
+		closeIndex := strings.Index(content[offset+len("<!--"):], "-->")
+		if closeIndex >= 0 {
+			commentEnd = offset + len("<!--") + closeIndex + len("-->")
+		}
+	}
+	return commentEnd
+}
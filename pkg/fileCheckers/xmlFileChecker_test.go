@@ -0,0 +1,211 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package fileCheckers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const expectedXmlHeader = `<!--
+ Copyright contributors to the Galasa project
+
+ SPDX-License-Identifier: EPL-2.0
+-->`
+
+func TestCheckXmlContentFindsCopyrightOk(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+<project/>
+`
+	var fileName = "test.xml"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckXmlContentFindsCopyrightOkAfterProlog(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<?xml version="1.0" encoding="UTF-8"?>
+<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+</project>
+`
+	var fileName = "pom.xml"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckHtmlContentFindsCopyrightOkAfterDoctype(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<!doctype html>
+
+<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+<html></html>
+`
+	var fileName = "test.html"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckXmlContentFindsCopyrightOkAfterPrologAndDoctypeWithInternalSubset(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<?xml version="1.0"?>
+<!DOCTYPE note [
+  <!ELEMENT note (#PCDATA)>
+]>
+<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+<note/>
+`
+	var fileName = "test.xml"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
+
+func TestCheckXmlContentFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<?xml version="1.0"?>
+<project/>
+`
+	var fileName = "test.xml"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckXmlContentWithCommentAfterFirstElementFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<project>
+<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+</project>
+`
+	var fileName = "test.xml"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckXmlContentFindsCopyrightMissing(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<?xml version="1.0"?>
+<!-- The build for the project -->
+<project/>
+`
+	var fileName = "test.xml"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in first comment block")
+	assert.Equal(t, 2, checkError.StartLine)
+}
+
+func TestCheckXmlContentFindsTooManyCopyright(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker()
+	var content = `<!--
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+
+  Copyright contributors to the Galasa project
+
+  SPDX-License-Identifier: EPL-2.0
+-->
+`
+	var fileName = "test.xml"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Found too many copyright texts in first comment block")
+}
+
+func TestFixXmlContentKeepsPrologFirst(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker().(FileFixer)
+	var content = `<?xml version="1.0"?>
+<!-- Copyright IBM Corp. 2021 -->
+<project/>
+`
+	var fileName = "pom.xml"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "<?xml version=\"1.0\"?>\n"+expectedXmlHeader+"\n<project/>\n", fixedContent)
+}
+
+func TestFixXmlContentWithPrologOnSameLineAsElement(t *testing.T) {
+	// Given
+	checker := NewXmlFileChecker().(FileFixer)
+	var content = `<?xml version="1.0"?><svg/>`
+	var fileName = "test.svg"
+
+	// When..
+	fixedContent, err := checker.FixFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "<?xml version=\"1.0\"?>\n"+expectedXmlHeader+"\n<svg/>", fixedContent)
+}
@@ -347,3 +347,58 @@ func TestCheckBashContentFindsCopyrightCommentJoinedWithAnotherComment(t *testin
 	// Then...
 	assert.Nil(t, checkError)
 }
+
+func TestCheckShellScriptFindsCopyrightMissingPointsAtCommentBlock(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker()
+	var content = `#!/bin/bash
+
+# Hello, world!
+# Goodbye, world!
+echo hello
+`
+	var fileName = "test.sh"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "Did not find copyright text in first comment block")
+	assert.Equal(t, 3, checkError.StartLine)
+	assert.Equal(t, 4, checkError.EndLine)
+}
+
+func TestCheckShellScriptWithOnlyShebangLineFindsNoComment(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker()
+	var content = `#!/bin/bash`
+	var fileName = "test.sh"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.NotNil(t, checkError)
+	assert.Contains(t, checkError.Message, "A comment block is missing at the start of the file")
+}
+
+func TestCheckScriptWithoutExtensionSkipsShebangLine(t *testing.T) {
+	// Given
+	checker := NewYamlFileChecker()
+	var content = `#!/bin/bash
+
+#
+# Copyright contributors to the Galasa project
+#
+# SPDX-License-Identifier: EPL-2.0
+#
+`
+	var fileName = "bin/build"
+
+	// When..
+	checkError := checker.CheckFileContent(content, fileName)
+
+	// Then...
+	assert.Nil(t, checkError)
+}
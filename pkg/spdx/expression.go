@@ -0,0 +1,256 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package spdx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The operators which join licences together. WITH binds tightest, then AND, then OR.
+const (
+	OPERATOR_AND  = "AND"
+	OPERATOR_OR   = "OR"
+	OPERATOR_WITH = "WITH"
+)
+
+// A parsed SPDX licence expression. eg: "(EPL-2.0 OR Apache-2.0) AND GPL-2.0-or-later WITH Classpath-exception-2.0"
+// See https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+//
+// An expression is either a single licence, or an AND or OR of other expressions.
+type Expression struct {
+	// For a single licence. eg: "Apache-2.0"
+	LicenseId string
+
+	// The licence was followed by '+', meaning "or any later version".
+	IsOrLater bool
+
+	// The exception named after WITH, if there is one. eg: "Classpath-exception-2.0"
+	ExceptionId string
+
+	// For an AND or OR of other expressions. Empty for a single licence.
+	Operator string
+	Operands []*Expression
+}
+
+// Licence and exception identifiers are letters, digits, '.' and '-'. Document references also have a ':'.
+var tokenPattern = regexp.MustCompile(`^([()+]|[A-Za-z0-9.:-]+)`)
+
+// Parses an SPDX licence expression, checking every identifier in it is on the SPDX licence list.
+// The operators can be in upper case or lower case, but not in mixed case.
+func ParseExpression(text string) (*Expression, error) {
+	var err error = nil
+	var expression *Expression = nil
+
+	parser := new(expressionParser)
+	parser.tokens, err = tokenise(text)
+
+	if err == nil {
+		if len(parser.tokens) == 0 {
+			err = errors.New("the licence expression is empty")
+		} else {
+			expression, err = parser.parseOr()
+		}
+	}
+
+	if err == nil && parser.position < len(parser.tokens) {
+		err = errors.New(fmt.Sprintf("unexpected '%s' in the licence expression", parser.tokens[parser.position]))
+	}
+
+	return expression, err
+}
+
+func tokenise(text string) ([]string, error) {
+	var err error = nil
+	tokens := make([]string, 0)
+
+	rest := strings.TrimSpace(text)
+	for err == nil && rest != "" {
+		token := tokenPattern.FindString(rest)
+		if token == "" {
+			err = errors.New(fmt.Sprintf("unexpected character '%c' in the licence expression", rest[0]))
+		} else {
+			tokens = append(tokens, token)
+			rest = strings.TrimSpace(rest[len(token):])
+		}
+	}
+
+	return tokens, err
+}
+
+// A recursive descent parser for the grammar:
+//
+//	or-expression   = and-expression *( "OR" and-expression )
+//	and-expression  = with-expression *( "AND" with-expression )
+//	with-expression = "(" or-expression ")" / licence-id [ "+" ] [ "WITH" exception-id ]
+type expressionParser struct {
+	tokens   []string
+	position int
+}
+
+func (this *expressionParser) peek() string {
+	token := ""
+	if this.position < len(this.tokens) {
+		token = this.tokens[this.position]
+	}
+	return token
+}
+
+// Is the next token the operator ? eg: "AND" or "and"
+func (this *expressionParser) isNextOperator(operator string) bool {
+	token := this.peek()
+	return token == operator || token == strings.ToLower(operator)
+}
+
+func (this *expressionParser) parseOr() (*Expression, error) {
+	return this.parseCompound(OPERATOR_OR, this.parseAnd)
+}
+
+func (this *expressionParser) parseAnd() (*Expression, error) {
+	return this.parseCompound(OPERATOR_AND, this.parseWith)
+}
+
+// Parses operands joined by the operator, using the parse function for each operand.
+func (this *expressionParser) parseCompound(operator string, parseOperand func() (*Expression, error)) (*Expression, error) {
+	expression, err := parseOperand()
+
+	if err == nil && this.isNextOperator(operator) {
+		operands := []*Expression{expression}
+		for err == nil && this.isNextOperator(operator) {
+			this.position++
+			expression, err = parseOperand()
+			operands = append(operands, expression)
+		}
+		expression = &Expression{Operator: operator, Operands: operands}
+	}
+
+	return expression, err
+}
+
+func (this *expressionParser) parseWith() (*Expression, error) {
+	var err error = nil
+	var expression *Expression = nil
+
+	token := this.peek()
+	switch {
+	case token == "":
+		err = errors.New("the licence expression ends too soon")
+
+	case token == "(":
+		this.position++
+		expression, err = this.parseOr()
+		if err == nil {
+			if this.peek() != ")" {
+				err = errors.New("a '(' in the licence expression has no matching ')'")
+			} else {
+				this.position++
+			}
+		}
+
+	case token == ")" || token == "+" || this.isOperator(token):
+		err = errors.New(fmt.Sprintf("expected a licence identifier but found '%s'", token))
+
+	default:
+		this.position++
+		expression = new(Expression)
+
+		var isKnown bool
+		expression.LicenseId, isKnown = GetLicenseId(token)
+		if !isKnown {
+			err = errors.New(fmt.Sprintf("'%s' is not a known SPDX licence identifier", token))
+		}
+
+		if err == nil && this.peek() == "+" {
+			this.position++
+			expression.IsOrLater = true
+		}
+
+		if err == nil && this.isNextOperator(OPERATOR_WITH) {
+			this.position++
+			exceptionToken := this.peek()
+			this.position++
+			expression.ExceptionId, isKnown = GetExceptionId(exceptionToken)
+			if exceptionToken == "" {
+				err = errors.New("expected a licence exception identifier after WITH")
+			} else if !isKnown {
+				err = errors.New(fmt.Sprintf("'%s' is not a known SPDX licence exception identifier", exceptionToken))
+			}
+		}
+	}
+
+	return expression, err
+}
+
+func (this *expressionParser) isOperator(token string) bool {
+	isOperator := false
+	for _, operator := range []string{OPERATOR_AND, OPERATOR_OR, OPERATOR_WITH} {
+		if token == operator || token == strings.ToLower(operator) {
+			isOperator = true
+		}
+	}
+	return isOperator
+}
+
+// Writes the expression out in the way the SPDX licence list writes its identifiers,
+// with upper case operators, and only the brackets which are needed.
+func (this *Expression) String() string {
+	return this.toString(false)
+}
+
+func (this *Expression) toString(isNormalised bool) string {
+	var text string
+	if this.Operator == "" {
+		text = this.LicenseId
+		if this.IsOrLater {
+			text += "+"
+		}
+		if this.ExceptionId != "" {
+			text += " " + OPERATOR_WITH + " " + this.ExceptionId
+		}
+	} else {
+		operandTexts := make([]string, 0, len(this.Operands))
+		for _, operand := range this.getOperands(isNormalised) {
+			operandText := operand.toString(isNormalised)
+			// An OR inside an AND needs brackets, as AND binds tighter.
+			if operand.Operator == OPERATOR_OR && this.Operator == OPERATOR_AND {
+				operandText = "(" + operandText + ")"
+			}
+			operandTexts = append(operandTexts, operandText)
+		}
+		if isNormalised {
+			sort.Strings(operandTexts)
+		}
+		text = strings.Join(operandTexts, " "+this.Operator+" ")
+	}
+	return text
+}
+
+// Gets the operands, taking nested operands with the same operator up into this level if normalising.
+// eg: "A AND (B AND C)" has the operands A, B and C
+func (this *Expression) getOperands(isNormalised bool) []*Expression {
+	operands := this.Operands
+	if isNormalised {
+		operands = make([]*Expression, 0, len(this.Operands))
+		for _, operand := range this.Operands {
+			if operand.Operator == this.Operator {
+				operands = append(operands, operand.getOperands(true)...)
+			} else {
+				operands = append(operands, operand)
+			}
+		}
+	}
+	return operands
+}
+
+// Do the two expressions mean the same ? The order of the operands of AND and OR doesn't matter,
+// nor do brackets which don't change the meaning, nor the case of the identifiers.
+// eg: "Apache-2.0 OR EPL-2.0" is equivalent to "(epl-2.0 OR Apache-2.0)"
+func (this *Expression) IsEquivalent(other *Expression) bool {
+	return this.toString(true) == other.toString(true)
+}
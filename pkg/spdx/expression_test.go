@@ -0,0 +1,131 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package spdx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSingleLicence(t *testing.T) {
+	// When..
+	expression, err := ParseExpression("EPL-2.0")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "EPL-2.0", expression.LicenseId)
+	assert.Equal(t, "", expression.Operator)
+}
+
+func TestParseGetsIdentifiersAsTheLicenceListWritesThem(t *testing.T) {
+	// When..
+	expression, err := ParseExpression("apache-2.0 or mit")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "Apache-2.0 OR MIT", expression.String())
+}
+
+func TestParseOrLaterAndException(t *testing.T) {
+	// When..
+	expression, err := ParseExpression("GPL-2.0+ WITH Classpath-exception-2.0")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "GPL-2.0", expression.LicenseId)
+	assert.True(t, expression.IsOrLater)
+	assert.Equal(t, "Classpath-exception-2.0", expression.ExceptionId)
+	assert.Equal(t, "GPL-2.0+ WITH Classpath-exception-2.0", expression.String())
+}
+
+func TestParseAndBindsTighterThanOr(t *testing.T) {
+	// When..
+	expression, err := ParseExpression("MIT OR Apache-2.0 AND EPL-2.0")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, OPERATOR_OR, expression.Operator)
+	assert.Equal(t, 2, len(expression.Operands))
+	assert.Equal(t, "MIT", expression.Operands[0].LicenseId)
+	assert.Equal(t, OPERATOR_AND, expression.Operands[1].Operator)
+}
+
+func TestParseBracketsChangeTheMeaning(t *testing.T) {
+	// When..
+	expression, err := ParseExpression("(MIT OR Apache-2.0) AND EPL-2.0")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, OPERATOR_AND, expression.Operator)
+	assert.Equal(t, "(MIT OR Apache-2.0) AND EPL-2.0", expression.String())
+}
+
+func TestParseLicenseRef(t *testing.T) {
+	// When..
+	expression, err := ParseExpression("LicenseRef-Galasa-Internal OR DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2")
+
+	// Then...
+	assert.Nil(t, err)
+	assert.Equal(t, "LicenseRef-Galasa-Internal", expression.Operands[0].LicenseId)
+	assert.Equal(t, "DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2", expression.Operands[1].LicenseId)
+}
+
+func TestParseRejectsUnknownLicence(t *testing.T) {
+	// When..
+	_, err := ParseExpression("EPL-2.0 OR Made-Up-1.0")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Equal(t, "'Made-Up-1.0' is not a known SPDX licence identifier", err.Error())
+}
+
+func TestParseRejectsUnknownException(t *testing.T) {
+	// When..
+	_, err := ParseExpression("GPL-2.0-only WITH Made-Up-exception")
+
+	// Then...
+	assert.NotNil(t, err)
+	assert.Equal(t, "'Made-Up-exception' is not a known SPDX licence exception identifier", err.Error())
+}
+
+func TestParseRejectsBadSyntax(t *testing.T) {
+	tests := []struct {
+		text            string
+		expectedMessage string
+	}{
+		{"", "the licence expression is empty"},
+		{"EPL-2.0 OR", "the licence expression ends too soon"},
+		{"(EPL-2.0 OR MIT", "a '(' in the licence expression has no matching ')'"},
+		{"EPL-2.0 MIT", "unexpected 'MIT' in the licence expression"},
+		{"AND MIT", "expected a licence identifier but found 'AND'"},
+		{"EPL-2.0 WITH", "expected a licence exception identifier after WITH"},
+		{"EPL-2.0 / MIT", "unexpected character '/' in the licence expression"},
+		{"EPL-2.0 Or MIT", "unexpected 'Or' in the licence expression"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			// When..
+			_, err := ParseExpression(test.text)
+
+			// Then...
+			assert.NotNil(t, err)
+			assert.Equal(t, test.expectedMessage, err.Error())
+		})
+	}
+}
+
+func TestEquivalentExpressionsIgnoreOrderBracketsAndCase(t *testing.T) {
+	// Given
+	expression, _ := ParseExpression("EPL-2.0 OR (Apache-2.0 OR mit)")
+	reordered, _ := ParseExpression("MIT OR EPL-2.0 OR Apache-2.0")
+	different, _ := ParseExpression("EPL-2.0 AND Apache-2.0 AND MIT")
+
+	// Then...
+	assert.True(t, expression.IsEquivalent(reordered))
+	assert.False(t, expression.IsEquivalent(different))
+}
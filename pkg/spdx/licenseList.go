@@ -0,0 +1,56 @@
+/*
+ * Copyright contributors to the Galasa project
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package spdx
+
+import (
+	_ "embed"
+	"regexp"
+	"strings"
+)
+
+//go:embed resources/license-ids.txt
+var licenseIdsText string
+
+//go:embed resources/exception-ids.txt
+var exceptionIdsText string
+
+// The known identifiers, indexed by their lower case form, as SPDX identifiers aren't case sensitive.
+// The values are the identifiers as the SPDX licence list writes them.
+var licenseIdsByLowerCase = parseIdList(licenseIdsText)
+var exceptionIdsByLowerCase = parseIdList(exceptionIdsText)
+
+// Licences which aren't on the SPDX licence list can be referred to like this. eg: "LicenseRef-Galasa-Internal"
+var licenseRefPattern = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.-]+:)?LicenseRef-[A-Za-z0-9.-]+$`)
+
+// Reads a list of identifiers, one per line. Lines starting with # are comments.
+func parseIdList(text string) map[string]string {
+	idsByLowerCase := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		id := strings.TrimSpace(line)
+		if id != "" && !strings.HasPrefix(id, "#") {
+			idsByLowerCase[strings.ToLower(id)] = id
+		}
+	}
+	return idsByLowerCase
+}
+
+// Looks up a licence identifier, getting the way the SPDX licence list writes it. eg: "apache-2.0" gives "Apache-2.0"
+// LicenseRef- identifiers are always known, and are returned unchanged.
+func GetLicenseId(id string) (string, bool) {
+	knownId, isKnown := licenseIdsByLowerCase[strings.ToLower(id)]
+	if !isKnown && licenseRefPattern.MatchString(id) {
+		knownId = id
+		isKnown = true
+	}
+	return knownId, isKnown
+}
+
+// Looks up a licence exception identifier, getting the way the SPDX licence list writes it.
+// eg: "classpath-exception-2.0" gives "Classpath-exception-2.0"
+func GetExceptionId(id string) (string, bool) {
+	knownId, isKnown := exceptionIdsByLowerCase[strings.ToLower(id)]
+	return knownId, isKnown
+}